@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	xpconversion "github.com/crossplane/provider-camunda/apis/internal/conversion"
+)
+
+const errNotClient = "object is not a v1beta1 Client"
+
+// clusterReference is the part of the hub version's parameters that this
+// version can't represent.
+type clusterReference struct {
+	ClusterIDRef      *xpv1.Reference `json:"clusterIDRef,omitempty"`
+	ClusterIDSelector *xpv1.Selector  `json:"clusterIDSelector,omitempty"`
+}
+
+// ConvertTo converts this Client to the hub version.
+func (mg *Client) ConvertTo(h conversion.Hub) error {
+	dst, ok := h.(*v1beta1.Client)
+	if !ok {
+		return errors.New(errNotClient)
+	}
+	mg.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	ref := clusterReference{}
+	if _, err := xpconversion.UnmarshalData(dst, &ref); err != nil {
+		return err
+	}
+
+	mg.Spec.ResourceSpec.DeepCopyInto(&dst.Spec.ResourceSpec)
+	dst.Spec.ForProvider = v1beta1.ClientParameters{
+		ClusterID:         mg.Spec.ForProvider.ClusterID,
+		ClusterIDRef:      ref.ClusterIDRef,
+		ClusterIDSelector: ref.ClusterIDSelector,
+	}
+
+	mg.Status.ResourceStatus.DeepCopyInto(&dst.Status.ResourceStatus)
+	o := mg.Status.AtProvider
+	dst.Status.AtProvider = v1beta1.ClientObservation{
+		ZeebeClientID:               o.ZeebeClientID,
+		ZeebeAddress:                o.ZeebeAddress,
+		ZeebeAuthorizationServerURL: o.ZeebeAuthorizationServerUrl,
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Client.
+func (mg *Client) ConvertFrom(h conversion.Hub) error {
+	src, ok := h.(*v1beta1.Client)
+	if !ok {
+		return errors.New(errNotClient)
+	}
+	src.ObjectMeta.DeepCopyInto(&mg.ObjectMeta)
+
+	src.Spec.ResourceSpec.DeepCopyInto(&mg.Spec.ResourceSpec)
+	p := src.Spec.ForProvider
+	mg.Spec.ForProvider = ClientParameters{ClusterID: p.ClusterID}
+
+	src.Status.ResourceStatus.DeepCopyInto(&mg.Status.ResourceStatus)
+	o := src.Status.AtProvider
+	mg.Status.AtProvider = ClientObservation{
+		ZeebeClientID:               o.ZeebeClientID,
+		ZeebeAddress:                o.ZeebeAddress,
+		ZeebeAuthorizationServerUrl: o.ZeebeAuthorizationServerURL,
+	}
+
+	if p.ClusterIDRef == nil && p.ClusterIDSelector == nil {
+		return nil
+	}
+	return xpconversion.MarshalData(mg, clusterReference{
+		ClusterIDRef:      p.ClusterIDRef.DeepCopy(),
+		ClusterIDSelector: p.ClusterIDSelector.DeepCopy(),
+	})
+}
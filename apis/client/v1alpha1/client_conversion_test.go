@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-camunda/apis/client/v1beta1"
+)
+
+func TestClientRoundTrip(t *testing.T) {
+	spoke := func() *Client {
+		cr := &Client{
+			ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+			Spec: ClientSpec{
+				ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+				ForProvider:  ClientParameters{ClusterID: "cool-cluster-id"},
+			},
+			Status: ClientStatus{AtProvider: ClientObservation{
+				ZeebeClientID:               "zeebe",
+				ZeebeAddress:                "zeebe.example.org:443",
+				ZeebeAuthorizationServerUrl: "https://login.example.org",
+			}},
+		}
+		cr.Status.SetConditions(xpv1.Available())
+		return cr
+	}
+
+	hub := func() *v1beta1.Client {
+		cr := &v1beta1.Client{
+			ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+			Spec: v1beta1.ClientSpec{
+				ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+				ForProvider: v1beta1.ClientParameters{
+					ClusterID:         "cool-cluster-id",
+					ClusterIDRef:      &xpv1.Reference{Name: "cool-cluster"},
+					ClusterIDSelector: &xpv1.Selector{MatchLabels: map[string]string{"team": "cool"}},
+				},
+			},
+			Status: v1beta1.ClientStatus{AtProvider: v1beta1.ClientObservation{
+				ZeebeClientID:               "zeebe",
+				ZeebeAddress:                "zeebe.example.org:443",
+				ZeebeAuthorizationServerURL: "https://login.example.org",
+			}},
+		}
+		cr.Status.SetConditions(xpv1.Available())
+		return cr
+	}
+
+	t.Run("SpokeHubSpoke", func(t *testing.T) {
+		h := &v1beta1.Client{}
+		if err := spoke().ConvertTo(h); err != nil {
+			t.Fatalf("ConvertTo(...): %v", err)
+		}
+		got := &Client{}
+		if err := got.ConvertFrom(h); err != nil {
+			t.Fatalf("ConvertFrom(...): %v", err)
+		}
+		if diff := cmp.Diff(spoke(), got, test.EquateConditions()); diff != "" {
+			t.Errorf("\nA v1alpha1 Client should survive a round trip through v1beta1.\nConvertFrom(ConvertTo(...)): -want, +got:\n%s\n", diff)
+		}
+	})
+
+	t.Run("HubSpokeHub", func(t *testing.T) {
+		s := &Client{}
+		if err := s.ConvertFrom(hub()); err != nil {
+			t.Fatalf("ConvertFrom(...): %v", err)
+		}
+		got := &v1beta1.Client{}
+		if err := s.ConvertTo(got); err != nil {
+			t.Fatalf("ConvertTo(...): %v", err)
+		}
+		if diff := cmp.Diff(hub(), got, test.EquateConditions()); diff != "" {
+			t.Errorf("\nA v1beta1 Client should survive a round trip through v1alpha1.\nConvertTo(ConvertFrom(...)): -want, +got:\n%s\n", diff)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ClientParameters are the configurable fields of a client.
+type ClientParameters struct {
+	// ClusterID is the UUID of the cluster the client belongs to. Either it,
+	// ClusterIDRef or ClusterIDSelector must be set.
+	// +optional
+	// +crossplane:generate:reference:type=github.com/crossplane/provider-camunda/apis/cluster/v1beta1.Cluster
+	ClusterID string `json:"clusterID,omitempty"`
+
+	// ClusterIDRef references the Cluster the client belongs to.
+	// +optional
+	ClusterIDRef *xpv1.Reference `json:"clusterIDRef,omitempty"`
+
+	// ClusterIDSelector selects the Cluster the client belongs to.
+	// +optional
+	ClusterIDSelector *xpv1.Selector `json:"clusterIDSelector,omitempty"`
+}
+
+// ClientObservation are the observable fields of a client.
+type ClientObservation struct {
+	// ZeebeClientID is the ID the client authenticates to Zeebe with.
+	// +optional
+	ZeebeClientID string `json:"zeebeClientID,omitempty"`
+
+	// ZeebeAddress is the address of the Zeebe gateway of the cluster.
+	// +optional
+	ZeebeAddress string `json:"zeebeAddress,omitempty"`
+
+	// ZeebeAuthorizationServerURL is the URL the client obtains access
+	// tokens from.
+	// +optional
+	ZeebeAuthorizationServerURL string `json:"zeebeAuthorizationServerURL,omitempty"`
+}
+
+// A ClientSpec defines the desired state of a client.
+type ClientSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ClientParameters `json:"forProvider"`
+}
+
+// A ClientStatus represents the observed state of a client.
+type ClientStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ClientObservation `json:"atProvider,omitempty"`
+}
+
+// ReasonWaitingForCluster indicates that a Client is not created yet because
+// the cluster it belongs to is not healthy.
+const ReasonWaitingForCluster xpv1.ConditionReason = "WaitingForCluster"
+
+// WaitingForCluster returns a condition that indicates the Client waits for
+// the cluster it belongs to before it is created.
+func WaitingForCluster(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingForCluster,
+		Message:            msg,
+	}
+}
+
+// +kubebuilder:object:root=true
+
+// A Client is providing access to a camunda cluster.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,camunda}
+type Client struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClientSpec   `json:"spec"`
+	Status ClientStatus `json:"status,omitempty"`
+}
+
+// Hub marks this version as the one other versions of Client are converted to
+// and from.
+func (*Client) Hub() {}
+
+// GetParameters of this Client.
+func (mg *Client) GetParameters() *ClientParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetObservation of this Client.
+func (mg *Client) GetObservation() *ClientObservation {
+	return &mg.Status.AtProvider
+}
+
+// +kubebuilder:object:root=true
+
+// ClientList contains a list of client
+type ClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Client `json:"items"`
+}
+
+// client type metadata.
+var (
+	ClientKind             = reflect.TypeOf(Client{}).Name()
+	ClientGroupKind        = schema.GroupKind{Group: Group, Kind: ClientKind}.String()
+	ClientKindAPIVersion   = ClientKind + "." + SchemeGroupVersion.String()
+	ClientGroupVersionKind = SchemeGroupVersion.WithKind(ClientKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Client{}, &ClientList{})
+}
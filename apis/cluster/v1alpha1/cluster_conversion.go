@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	xpconversion "github.com/crossplane/provider-camunda/apis/internal/conversion"
+)
+
+const errNotCluster = "object is not a v1beta1 Cluster"
+
+// ConvertTo converts this Cluster to the hub version.
+func (mg *Cluster) ConvertTo(h conversion.Hub) error {
+	dst, ok := h.(*v1beta1.Cluster)
+	if !ok {
+		return errors.New(errNotCluster)
+	}
+	mg.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	// The health of the components of a cluster can't be represented by this
+	// version, but it may have been preserved when converting from the hub.
+	health := v1beta1.ClusterHealth{}
+	if _, err := xpconversion.UnmarshalData(dst, &health); err != nil {
+		return err
+	}
+
+	mg.Spec.ResourceSpec.DeepCopyInto(&dst.Spec.ResourceSpec)
+	p := mg.Spec.ForProvider
+	dst.Spec.ForProvider = v1beta1.ClusterParameters{
+		Channel:                p.Channel,
+		Generation:             p.Generation,
+		Region:                 p.Region,
+		PlanType:               p.PlanType,
+		DeletionProtection:     p.DeletionProtection,
+		DependentClientsPolicy: v1beta1.DependentClientsPolicy(p.DependentClientsPolicy),
+	}
+
+	mg.Status.ResourceStatus.DeepCopyInto(&dst.Status.ResourceStatus)
+	o := mg.Status.AtProvider
+	dst.Status.AtProvider = v1beta1.ClusterObservation{
+		Health: v1beta1.ClusterHealth{
+			Ready:    o.Ready,
+			Zeebe:    health.Zeebe,
+			Operate:  health.Operate,
+			Tasklist: health.Tasklist,
+			Optimize: health.Optimize,
+		},
+		Endpoints: v1beta1.ClusterEndpoints{
+			Zeebe:    o.Zeebe,
+			Operate:  o.Operate,
+			Tasklist: o.Tasklist,
+			Optimize: o.Optimize,
+		},
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Cluster.
+func (mg *Cluster) ConvertFrom(h conversion.Hub) error {
+	src, ok := h.(*v1beta1.Cluster)
+	if !ok {
+		return errors.New(errNotCluster)
+	}
+	src.ObjectMeta.DeepCopyInto(&mg.ObjectMeta)
+
+	src.Spec.ResourceSpec.DeepCopyInto(&mg.Spec.ResourceSpec)
+	p := src.Spec.ForProvider
+	mg.Spec.ForProvider = ClusterParameters{
+		Channel:                p.Channel,
+		Generation:             p.Generation,
+		Region:                 p.Region,
+		PlanType:               p.PlanType,
+		DeletionProtection:     p.DeletionProtection,
+		DependentClientsPolicy: DependentClientsPolicy(p.DependentClientsPolicy),
+	}
+
+	src.Status.ResourceStatus.DeepCopyInto(&mg.Status.ResourceStatus)
+	o := src.Status.AtProvider
+	mg.Status.AtProvider = ClusterObservation{
+		Ready:    o.Health.Ready,
+		Zeebe:    o.Endpoints.Zeebe,
+		Operate:  o.Endpoints.Operate,
+		Tasklist: o.Endpoints.Tasklist,
+		Optimize: o.Endpoints.Optimize,
+	}
+
+	if o.Health == (v1beta1.ClusterHealth{Ready: o.Health.Ready}) {
+		return nil
+	}
+	return xpconversion.MarshalData(mg, v1beta1.ClusterHealth{
+		Zeebe:    o.Health.Zeebe,
+		Operate:  o.Health.Operate,
+		Tasklist: o.Health.Tasklist,
+		Optimize: o.Health.Optimize,
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+)
+
+func spoke() *Cluster {
+	cr := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cool-cluster",
+			Annotations: map[string]string{"crossplane.io/external-name": "cool-id"},
+		},
+		Spec: ClusterSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+			ForProvider: ClusterParameters{
+				Channel:                "channel",
+				Generation:             "generation",
+				Region:                 "region",
+				PlanType:               "plan",
+				DeletionProtection:     true,
+				DependentClientsPolicy: DependentClientsCascade,
+			},
+		},
+		Status: ClusterStatus{AtProvider: ClusterObservation{
+			Ready:    "Healthy",
+			Zeebe:    "zeebe.example.org",
+			Operate:  "operate.example.org",
+			Tasklist: "tasklist.example.org",
+			Optimize: "optimize.example.org",
+		}},
+	}
+	cr.Status.SetConditions(xpv1.Available())
+	return cr
+}
+
+func hub() *v1beta1.Cluster {
+	cr := &v1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cool-cluster",
+			Annotations: map[string]string{"crossplane.io/external-name": "cool-id"},
+		},
+		Spec: v1beta1.ClusterSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+			ForProvider: v1beta1.ClusterParameters{
+				Channel:                "channel",
+				Generation:             "generation",
+				Region:                 "region",
+				PlanType:               "plan",
+				DeletionProtection:     true,
+				DependentClientsPolicy: v1beta1.DependentClientsCascade,
+			},
+		},
+		Status: v1beta1.ClusterStatus{AtProvider: v1beta1.ClusterObservation{
+			Health: v1beta1.ClusterHealth{
+				Ready:    "Healthy",
+				Zeebe:    "Healthy",
+				Operate:  "Unhealthy",
+				Tasklist: "Healthy",
+				Optimize: "Creating",
+			},
+			Endpoints: v1beta1.ClusterEndpoints{
+				Zeebe:    "zeebe.example.org",
+				Operate:  "operate.example.org",
+				Tasklist: "tasklist.example.org",
+				Optimize: "optimize.example.org",
+			},
+		}},
+	}
+	cr.Status.SetConditions(xpv1.Available())
+	return cr
+}
+
+func TestClusterRoundTrip(t *testing.T) {
+	t.Run("SpokeHubSpoke", func(t *testing.T) {
+		h := &v1beta1.Cluster{}
+		if err := spoke().ConvertTo(h); err != nil {
+			t.Fatalf("ConvertTo(...): %v", err)
+		}
+		got := &Cluster{}
+		if err := got.ConvertFrom(h); err != nil {
+			t.Fatalf("ConvertFrom(...): %v", err)
+		}
+		if diff := cmp.Diff(spoke(), got, test.EquateConditions()); diff != "" {
+			t.Errorf("\nA v1alpha1 Cluster should survive a round trip through v1beta1.\nConvertFrom(ConvertTo(...)): -want, +got:\n%s\n", diff)
+		}
+	})
+
+	t.Run("HubSpokeHub", func(t *testing.T) {
+		s := &Cluster{}
+		if err := s.ConvertFrom(hub()); err != nil {
+			t.Fatalf("ConvertFrom(...): %v", err)
+		}
+		got := &v1beta1.Cluster{}
+		if err := s.ConvertTo(got); err != nil {
+			t.Fatalf("ConvertTo(...): %v", err)
+		}
+		if diff := cmp.Diff(hub(), got, test.EquateConditions()); diff != "" {
+			t.Errorf("\nA v1beta1 Cluster should survive a round trip through v1alpha1.\nConvertTo(ConvertFrom(...)): -want, +got:\n%s\n", diff)
+		}
+	})
+}
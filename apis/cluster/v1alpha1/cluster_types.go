@@ -24,16 +24,61 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
-// ClusterParameters are the configurable fields of a Cluster.
+// ClusterParameters are the configurable fields of a Cluster. Fields that are
+// left empty are late-initialized from the observed cluster, which allows an
+// existing cluster to be imported by its external-name alone.
 type ClusterParameters struct {
-	Channel    string `json:"channel"`
-	Generation string `json:"generation"`
-	Region     string `json:"region"`
-	PlanType   string `json:"planType"`
+	// Channel is the UUID of the release channel of the cluster.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Generation is the UUID of the generation of the cluster.
+	// +optional
+	Generation string `json:"generation,omitempty"`
+
+	// Region is the UUID of the region the cluster runs in.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// PlanType is the UUID of the plan type of the cluster.
+	// +optional
+	PlanType string `json:"planType,omitempty"`
+
+	// DeletionProtection prevents the cluster from being deleted while it is
+	// set. It must be removed before the Cluster can be deleted.
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// DependentClientsPolicy determines what happens when the Cluster is
+	// deleted while Clients still reference it. Block delays the deletion
+	// until the Clients are gone, Cascade deletes the Clients first.
+	// +optional
+	// +kubebuilder:validation:Enum=Block;Cascade
+	// +kubebuilder:default=Block
+	DependentClientsPolicy DependentClientsPolicy `json:"dependentClientsPolicy,omitempty"`
 }
 
+// A DependentClientsPolicy determines how the deletion of a Cluster treats the
+// Clients that reference it.
+type DependentClientsPolicy string
+
+// Dependent clients policies.
+const (
+	// DependentClientsBlock delays the deletion of a Cluster until all Clients
+	// that reference it are gone.
+	DependentClientsBlock DependentClientsPolicy = "Block"
+
+	// DependentClientsCascade deletes all Clients that reference a Cluster
+	// before the Cluster is deleted.
+	DependentClientsCascade DependentClientsPolicy = "Cascade"
+)
+
 // ClusterObservation are the observable fields of a Cluster.
 type ClusterObservation struct {
+	// Ready is the overall health of the cluster, for example Creating or
+	// Healthy.
+	Ready string `json:"ready,omitempty"`
+
 	Operate  string `json:"operate,omitempty"`
 	Optimize string `json:"optimize,omitempty"`
 	Tasklist string `json:"tasklist,omitempty"`
@@ -0,0 +1,258 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+// ClusterParameters are the configurable fields of a Cluster. Fields that are
+// left empty are defaulted from the ProviderConfig or late-initialized from
+// the observed cluster, which allows an existing cluster to be imported by its
+// external-name alone.
+type ClusterParameters struct {
+	// Channel is the UUID of the release channel of the cluster.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Generation is the UUID of the generation of the cluster.
+	// +optional
+	Generation string `json:"generation,omitempty"`
+
+	// Region is the UUID of the region the cluster runs in.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// PlanType is the UUID of the plan type of the cluster.
+	// +optional
+	PlanType string `json:"planType,omitempty"`
+
+	// DeletionProtection prevents the cluster from being deleted while it is
+	// set. It must be removed before the Cluster can be deleted.
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// DependentClientsPolicy determines what happens when the Cluster is
+	// deleted while Clients still reference it. Block delays the deletion
+	// until the Clients are gone, Cascade deletes the Clients first.
+	// +optional
+	// +kubebuilder:validation:Enum=Block;Cascade
+	// +kubebuilder:default=Block
+	DependentClientsPolicy DependentClientsPolicy `json:"dependentClientsPolicy,omitempty"`
+}
+
+// ApplyDefaults fills the empty parameters from the supplied defaults. It
+// returns true if any parameter was changed.
+func (p *ClusterParameters) ApplyDefaults(d *apisv1beta1.ClusterDefaults) bool {
+	if d == nil {
+		return false
+	}
+	changed := false
+	for _, f := range []struct {
+		param *string
+		def   string
+	}{
+		{param: &p.Channel, def: d.Channel},
+		{param: &p.Generation, def: d.Generation},
+		{param: &p.Region, def: d.Region},
+		{param: &p.PlanType, def: d.PlanType},
+	} {
+		if *f.param == "" && f.def != "" {
+			*f.param = f.def
+			changed = true
+		}
+	}
+	return changed
+}
+
+// A DependentClientsPolicy determines how the deletion of a Cluster treats the
+// Clients that reference it.
+type DependentClientsPolicy string
+
+// Dependent clients policies.
+const (
+	// DependentClientsBlock delays the deletion of a Cluster until all Clients
+	// that reference it are gone.
+	DependentClientsBlock DependentClientsPolicy = "Block"
+
+	// DependentClientsCascade deletes all Clients that reference a Cluster
+	// before the Cluster is deleted.
+	DependentClientsCascade DependentClientsPolicy = "Cascade"
+)
+
+// ClusterHealth is the health of a cluster and its components, for example
+// Creating or Healthy.
+type ClusterHealth struct {
+	// Ready is the overall health of the cluster.
+	// +optional
+	Ready string `json:"ready,omitempty"`
+
+	// +optional
+	Zeebe string `json:"zeebe,omitempty"`
+
+	// +optional
+	Operate string `json:"operate,omitempty"`
+
+	// +optional
+	Tasklist string `json:"tasklist,omitempty"`
+
+	// +optional
+	Optimize string `json:"optimize,omitempty"`
+}
+
+// ClusterEndpoints are the URLs of the components of a cluster.
+type ClusterEndpoints struct {
+	// +optional
+	Zeebe string `json:"zeebe,omitempty"`
+
+	// +optional
+	Operate string `json:"operate,omitempty"`
+
+	// +optional
+	Tasklist string `json:"tasklist,omitempty"`
+
+	// +optional
+	Optimize string `json:"optimize,omitempty"`
+}
+
+// ClusterObservation are the observable fields of a Cluster.
+type ClusterObservation struct {
+	// Health of the cluster and its components.
+	// +optional
+	Health ClusterHealth `json:"health,omitempty"`
+
+	// Endpoints of the components of the cluster.
+	// +optional
+	Endpoints ClusterEndpoints `json:"endpoints,omitempty"`
+}
+
+// A ClusterSpec defines the desired state of a Cluster.
+type ClusterSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ClusterParameters `json:"forProvider,omitempty"`
+}
+
+// A ClusterStatus represents the observed state of a Cluster.
+type ClusterStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ClusterObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Cluster Camunda 8 Platform SaaS Cluster
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".status.atProvider.health.ready"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,camunda}
+type Cluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSpec   `json:"spec"`
+	Status ClusterStatus `json:"status,omitempty"`
+}
+
+// Hub marks this version as the one other versions of Cluster are converted
+// to and from.
+func (*Cluster) Hub() {}
+
+// GetParameters of this Cluster.
+func (mg *Cluster) GetParameters() *ClusterParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetObservation of this Cluster.
+func (mg *Cluster) GetObservation() *ClusterObservation {
+	return &mg.Status.AtProvider
+}
+
+// AnnotationKeyDeletionProtection is an alternative to the deletionProtection
+// field. A Cluster annotated with it set to "true" is protected from deletion.
+const AnnotationKeyDeletionProtection = "camunda.crossplane.io/deletion-protection"
+
+// DeletionProtected returns true if the Cluster is protected from deletion by
+// either its deletionProtection field or its deletion protection annotation.
+func (mg *Cluster) DeletionProtected() bool {
+	return mg.Spec.ForProvider.DeletionProtection || mg.GetAnnotations()[AnnotationKeyDeletionProtection] == "true"
+}
+
+// TypeDeletionProtected indicates that the deletion of a Cluster is blocked by
+// its deletion protection. It is a condition of its own, since the reconciler
+// overwrites the Ready and Synced conditions when a deletion fails.
+const TypeDeletionProtected xpv1.ConditionType = "DeletionProtected"
+
+// Reasons a Cluster is or is not protected from deletion.
+const (
+	ReasonDeletionProtected   xpv1.ConditionReason = "DeletionProtected"
+	ReasonDeletionUnprotected xpv1.ConditionReason = "DeletionUnprotected"
+)
+
+// ProtectedFromDeletion returns a condition that indicates the deletion of a
+// Cluster is blocked by its deletion protection.
+func ProtectedFromDeletion(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionProtected,
+		Message:            msg,
+	}
+}
+
+// UnprotectedFromDeletion returns a condition that indicates the deletion of a
+// Cluster is no longer blocked by its deletion protection.
+func UnprotectedFromDeletion() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionUnprotected,
+	}
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterList contains a list of Cluster
+type ClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Cluster `json:"items"`
+}
+
+// Cluster type metadata.
+var (
+	ClusterKind             = reflect.TypeOf(Cluster{}).Name()
+	ClusterGroupKind        = schema.GroupKind{Group: Group, Kind: ClusterKind}.String()
+	ClusterKindAPIVersion   = ClusterKind + "." + SchemeGroupVersion.String()
+	ClusterGroupVersionKind = SchemeGroupVersion.WithKind(ClusterKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
+}
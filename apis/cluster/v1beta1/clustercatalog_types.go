@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// A CatalogEntry is a parameter a Cluster may use.
+type CatalogEntry struct {
+	// ID is the UUID to set in the parameters of a Cluster.
+	ID string `json:"id"`
+
+	// Name is the human readable name of the parameter.
+	Name string `json:"name"`
+}
+
+// A CatalogChannel is a release channel a Cluster may use.
+type CatalogChannel struct {
+	CatalogEntry `json:",inline"`
+
+	// DefaultGeneration is the generation new clusters of the channel are
+	// created with by the Console.
+	// +optional
+	DefaultGeneration *CatalogEntry `json:"defaultGeneration,omitempty"`
+
+	// Generations are the generations a Cluster of the channel may be
+	// created with or changed to. The Console API does not tell which
+	// generations a cluster can be upgraded to from its current one.
+	// +optional
+	Generations []CatalogEntry `json:"generations,omitempty"`
+}
+
+// ClusterCatalogObservation are the cluster parameters the Console API offers.
+type ClusterCatalogObservation struct {
+	// Channels are the release channels and their generations.
+	// +optional
+	Channels []CatalogChannel `json:"channels,omitempty"`
+
+	// Regions are the regions clusters may run in.
+	// +optional
+	Regions []CatalogEntry `json:"regions,omitempty"`
+
+	// PlanTypes are the plan types clusters may have.
+	// +optional
+	PlanTypes []CatalogEntry `json:"planTypes,omitempty"`
+}
+
+// A ClusterCatalogSpec defines which ProviderConfig a ClusterCatalog lists the
+// cluster parameters of.
+type ClusterCatalogSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+}
+
+// A ClusterCatalogStatus represents the cluster parameters the Console API
+// offers.
+type ClusterCatalogStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ClusterCatalogObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ClusterCatalog lists the channels, generations, regions and plan types
+// the Console API offers to the credentials of its ProviderConfig. It is read
+// only and refreshed periodically; deleting it does not affect the Console.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROVIDERCONFIG",type="string",JSONPath=".spec.providerConfigRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,camunda}
+type ClusterCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterCatalogSpec   `json:"spec"`
+	Status ClusterCatalogStatus `json:"status,omitempty"`
+}
+
+// GetObservation of this ClusterCatalog.
+func (mg *ClusterCatalog) GetObservation() *ClusterCatalogObservation {
+	return &mg.Status.AtProvider
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterCatalogList contains a list of ClusterCatalog
+type ClusterCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterCatalog `json:"items"`
+}
+
+// ClusterCatalog type metadata.
+var (
+	ClusterCatalogKind             = reflect.TypeOf(ClusterCatalog{}).Name()
+	ClusterCatalogGroupKind        = schema.GroupKind{Group: Group, Kind: ClusterCatalogKind}.String()
+	ClusterCatalogKindAPIVersion   = ClusterCatalogKind + "." + SchemeGroupVersion.String()
+	ClusterCatalogGroupVersionKind = SchemeGroupVersion.WithKind(ClusterCatalogKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ClusterCatalog{}, &ClusterCatalogList{})
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MinDeleteAfter is the shortest grace period after which an OrphanReport
+// deletes unmanaged resources.
+const MinDeleteAfter = time.Hour
+
+// OrphanReportParameters are the configurable fields of an OrphanReport.
+type OrphanReportParameters struct {
+	// DeleteAfter opts in to deleting unmanaged clusters and clients once they
+	// have been reported for at least this long, for example "72h". It must
+	// be at least 1h. Unmanaged resources are only reported if it is not set.
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1h')",message="deleteAfter must be at least 1h"
+	// +optional
+	DeleteAfter *metav1.Duration `json:"deleteAfter,omitempty"`
+}
+
+// An UnmanagedCluster is a cluster no Cluster manages.
+type UnmanagedCluster struct {
+	// ID is the UUID of the cluster.
+	ID string `json:"id"`
+
+	// Name is the name of the cluster in the Console.
+	Name string `json:"name"`
+
+	// FirstSeen is when the cluster was first reported.
+	FirstSeen metav1.Time `json:"firstSeen"`
+}
+
+// An UnmanagedClient is a client of a managed cluster that no Client manages.
+type UnmanagedClient struct {
+	// ID is the client ID of the client.
+	ID string `json:"id"`
+
+	// Name is the name of the client in the Console.
+	Name string `json:"name"`
+
+	// ClusterID is the UUID of the cluster the client belongs to.
+	ClusterID string `json:"clusterID"`
+
+	// FirstSeen is when the client was first reported.
+	FirstSeen metav1.Time `json:"firstSeen"`
+}
+
+// OrphanReportObservation are the clusters and clients of the organization
+// that are not managed by any Cluster or Client.
+type OrphanReportObservation struct {
+	// Clusters no Cluster manages. Their clients are not reported
+	// separately.
+	// +optional
+	Clusters []UnmanagedCluster `json:"clusters,omitempty"`
+
+	// Clients of managed clusters that no Client manages.
+	// +optional
+	Clients []UnmanagedClient `json:"clients,omitempty"`
+
+	// DeletionsHeldBack is true while a Cluster or Client has not recorded
+	// the ID of its external resource, for example because it is being
+	// created. Its cluster or client would look unmanaged, so nothing is
+	// deleted until all of them have.
+	// +optional
+	DeletionsHeldBack bool `json:"deletionsHeldBack,omitempty"`
+}
+
+// An OrphanReportSpec defines which ProviderConfig an OrphanReport checks the
+// organization of, and whether unmanaged resources are deleted.
+type OrphanReportSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       OrphanReportParameters `json:"forProvider,omitempty"`
+}
+
+// An OrphanReportStatus represents the unmanaged resources of the
+// organization.
+type OrphanReportStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          OrphanReportObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An OrphanReport lists the clusters and clients of the organization of its
+// ProviderConfig whose IDs are not the external-name of any Cluster or Client,
+// for example because they were created in the Console or orphaned by a
+// deleted managed resource. It is refreshed periodically and, if opted in,
+// deletes them after a grace period. Deleting it does not affect the Console.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="DELETE-AFTER",type="string",JSONPath=".spec.forProvider.deleteAfter"
+// +kubebuilder:printcolumn:name="PROVIDERCONFIG",type="string",JSONPath=".spec.providerConfigRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,camunda}
+type OrphanReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrphanReportSpec   `json:"spec"`
+	Status OrphanReportStatus `json:"status,omitempty"`
+}
+
+// GetParameters of this OrphanReport.
+func (mg *OrphanReport) GetParameters() *OrphanReportParameters {
+	return &mg.Spec.ForProvider
+}
+
+// GetObservation of this OrphanReport.
+func (mg *OrphanReport) GetObservation() *OrphanReportObservation {
+	return &mg.Status.AtProvider
+}
+
+// +kubebuilder:object:root=true
+
+// OrphanReportList contains a list of OrphanReport
+type OrphanReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OrphanReport `json:"items"`
+}
+
+// OrphanReport type metadata.
+var (
+	OrphanReportKind             = reflect.TypeOf(OrphanReport{}).Name()
+	OrphanReportGroupKind        = schema.GroupKind{Group: Group, Kind: OrphanReportKind}.String()
+	OrphanReportKindAPIVersion   = OrphanReportKind + "." + SchemeGroupVersion.String()
+	OrphanReportGroupVersionKind = SchemeGroupVersion.WithKind(OrphanReportKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&OrphanReport{}, &OrphanReportList{})
+}
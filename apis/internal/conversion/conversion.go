@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion helps converting between API versions without losing
+// the fields that only some versions have.
+package conversion
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyData is the annotation that preserves the fields of the hub
+// version of a resource that an older version cannot represent.
+const AnnotationKeyData = "camunda.crossplane.io/conversion-data"
+
+const (
+	errMarshalData   = "cannot marshal conversion data"
+	errUnmarshalData = "cannot unmarshal conversion data"
+)
+
+// MarshalData stores the supplied data in the conversion data annotation of
+// the supplied object.
+func MarshalData(o metav1.Object, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, errMarshalData)
+	}
+	a := o.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[AnnotationKeyData] = string(b)
+	o.SetAnnotations(a)
+	return nil
+}
+
+// UnmarshalData reads the conversion data annotation of the supplied object
+// into the supplied data and removes the annotation. It returns false if the
+// object has no conversion data.
+func UnmarshalData(o metav1.Object, data any) (bool, error) {
+	a := o.GetAnnotations()
+	s, ok := a[AnnotationKeyData]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(s), data); err != nil {
+		return false, errors.Wrap(err, errUnmarshalData)
+	}
+	delete(a, AnnotationKeyData)
+	if len(a) == 0 {
+		a = nil
+	}
+	o.SetAnnotations(a)
+	return true, nil
+}
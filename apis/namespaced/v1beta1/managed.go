@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// The managed resources of this package are written by hand rather than
+// generated, since their connection secrets and ProviderConfigs are local to
+// their namespace.
+
+// GetCondition of this Cluster.
+func (mg *Cluster) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetDeletionPolicy of this Cluster.
+func (mg *Cluster) GetDeletionPolicy() xpv1.DeletionPolicy {
+	return mg.Spec.DeletionPolicy
+}
+
+// GetProviderConfigReference of this Cluster.
+func (mg *Cluster) GetProviderConfigReference() *xpv1.Reference {
+	return mg.Spec.ProviderConfigReference
+}
+
+/*
+GetProviderReference of this Cluster. Namespaced managed resources have no
+provider reference, so it is always nil.
+Deprecated: Use GetProviderConfigReference.
+*/
+func (mg *Cluster) GetProviderReference() *xpv1.Reference {
+	return nil
+}
+
+// GetPublishConnectionDetailsTo of this Cluster. Namespaced managed resources
+// cannot publish to a StoreConfig, which is cluster scoped, so it is always
+// nil.
+func (mg *Cluster) GetPublishConnectionDetailsTo() *xpv1.PublishConnectionDetailsTo {
+	return nil
+}
+
+// GetWriteConnectionSecretToReference of this Cluster. The referenced Secret is
+// always in the namespace of the Cluster.
+func (mg *Cluster) GetWriteConnectionSecretToReference() *xpv1.SecretReference {
+	if mg.Spec.WriteConnectionSecretToReference == nil {
+		return nil
+	}
+	return &xpv1.SecretReference{Name: mg.Spec.WriteConnectionSecretToReference.Name, Namespace: mg.GetNamespace()}
+}
+
+// SetConditions of this Cluster.
+func (mg *Cluster) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetDeletionPolicy of this Cluster.
+func (mg *Cluster) SetDeletionPolicy(r xpv1.DeletionPolicy) {
+	mg.Spec.DeletionPolicy = r
+}
+
+// SetProviderConfigReference of this Cluster.
+func (mg *Cluster) SetProviderConfigReference(r *xpv1.Reference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+/*
+SetProviderReference of this Cluster. Namespaced managed resources have no
+provider reference, so it is ignored.
+Deprecated: Use SetProviderConfigReference.
+*/
+func (mg *Cluster) SetProviderReference(_ *xpv1.Reference) {}
+
+// SetPublishConnectionDetailsTo of this Cluster. Namespaced managed resources
+// cannot publish to a StoreConfig, so it is ignored.
+func (mg *Cluster) SetPublishConnectionDetailsTo(_ *xpv1.PublishConnectionDetailsTo) {}
+
+// SetWriteConnectionSecretToReference of this Cluster. The namespace of the
+// supplied reference is ignored.
+func (mg *Cluster) SetWriteConnectionSecretToReference(r *xpv1.SecretReference) {
+	if r == nil {
+		mg.Spec.WriteConnectionSecretToReference = nil
+		return
+	}
+	mg.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: r.Name}
+}
+
+// GetItems of this ClusterList.
+func (l *ClusterList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
+// GetCondition of this Client.
+func (mg *Client) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetDeletionPolicy of this Client.
+func (mg *Client) GetDeletionPolicy() xpv1.DeletionPolicy {
+	return mg.Spec.DeletionPolicy
+}
+
+// GetProviderConfigReference of this Client.
+func (mg *Client) GetProviderConfigReference() *xpv1.Reference {
+	return mg.Spec.ProviderConfigReference
+}
+
+/*
+GetProviderReference of this Client. Namespaced managed resources have no
+provider reference, so it is always nil.
+Deprecated: Use GetProviderConfigReference.
+*/
+func (mg *Client) GetProviderReference() *xpv1.Reference {
+	return nil
+}
+
+// GetPublishConnectionDetailsTo of this Client. Namespaced managed resources
+// cannot publish to a StoreConfig, which is cluster scoped, so it is always
+// nil.
+func (mg *Client) GetPublishConnectionDetailsTo() *xpv1.PublishConnectionDetailsTo {
+	return nil
+}
+
+// GetWriteConnectionSecretToReference of this Client. The referenced Secret is
+// always in the namespace of the Client.
+func (mg *Client) GetWriteConnectionSecretToReference() *xpv1.SecretReference {
+	if mg.Spec.WriteConnectionSecretToReference == nil {
+		return nil
+	}
+	return &xpv1.SecretReference{Name: mg.Spec.WriteConnectionSecretToReference.Name, Namespace: mg.GetNamespace()}
+}
+
+// SetConditions of this Client.
+func (mg *Client) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetDeletionPolicy of this Client.
+func (mg *Client) SetDeletionPolicy(r xpv1.DeletionPolicy) {
+	mg.Spec.DeletionPolicy = r
+}
+
+// SetProviderConfigReference of this Client.
+func (mg *Client) SetProviderConfigReference(r *xpv1.Reference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+/*
+SetProviderReference of this Client. Namespaced managed resources have no
+provider reference, so it is ignored.
+Deprecated: Use SetProviderConfigReference.
+*/
+func (mg *Client) SetProviderReference(_ *xpv1.Reference) {}
+
+// SetPublishConnectionDetailsTo of this Client. Namespaced managed resources
+// cannot publish to a StoreConfig, so it is ignored.
+func (mg *Client) SetPublishConnectionDetailsTo(_ *xpv1.PublishConnectionDetailsTo) {}
+
+// SetWriteConnectionSecretToReference of this Client. The namespace of the
+// supplied reference is ignored.
+func (mg *Client) SetWriteConnectionSecretToReference(r *xpv1.SecretReference) {
+	if r == nil {
+		mg.Spec.WriteConnectionSecretToReference = nil
+		return
+	}
+	mg.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: r.Name}
+}
+
+// GetItems of this ClientList.
+func (l *ClientList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
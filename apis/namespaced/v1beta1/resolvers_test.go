@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+)
+
+func TestResolveReferences(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cluster := func(name string) Cluster {
+		cr := Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name}}
+		meta.SetExternalName(&cr, "cool-id")
+		return cr
+	}
+
+	// kube only serves Clusters from the namespace "team-a".
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Namespace != "team-a" {
+				return errBoom
+			}
+			*obj.(*Cluster) = cluster(key.Name)
+			return nil
+		},
+		MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.Namespace != "team-a" {
+				return errBoom
+			}
+			list.(*ClusterList).Items = []Cluster{cluster("selected-cluster")}
+			return nil
+		},
+	}
+
+	newClient := func(p clientv1beta1.ClientParameters) *Client {
+		return &Client{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cool-client"},
+			Spec:       ClientSpec{ForProvider: p},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *Client
+		want   clientv1beta1.ClientParameters
+	}{
+		"Reference": {
+			reason: "A reference should be resolved to a Cluster in the Client's namespace.",
+			mg:     newClient(clientv1beta1.ClientParameters{ClusterIDRef: &xpv1.Reference{Name: "cool-cluster"}}),
+			want: clientv1beta1.ClientParameters{
+				ClusterID:    "cool-id",
+				ClusterIDRef: &xpv1.Reference{Name: "cool-cluster"},
+			},
+		},
+		"Selector": {
+			reason: "A selector should select a Cluster in the Client's namespace.",
+			mg:     newClient(clientv1beta1.ClientParameters{ClusterIDSelector: &xpv1.Selector{MatchLabels: map[string]string{"cool": "true"}}}),
+			want: clientv1beta1.ClientParameters{
+				ClusterID:         "cool-id",
+				ClusterIDRef:      &xpv1.Reference{Name: "selected-cluster"},
+				ClusterIDSelector: &xpv1.Selector{MatchLabels: map[string]string{"cool": "true"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.mg.ResolveReferences(context.Background(), kube); err != nil {
+				t.Errorf("\n%s\nmg.ResolveReferences(...): unexpected error: %v\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.mg.Spec.ForProvider); diff != "" {
+				t.Errorf("\n%s\nmg.ResolveReferences(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetWriteConnectionSecretToReference(t *testing.T) {
+	cr := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+		Spec:       ClusterSpec{ResourceSpec: ResourceSpec{WriteConnectionSecretToReference: &xpv1.LocalSecretReference{Name: "cool-secret"}}},
+	}
+	want := &xpv1.SecretReference{Namespace: "team-a", Name: "cool-secret"}
+	if diff := cmp.Diff(want, cr.GetWriteConnectionSecretToReference()); diff != "" {
+		t.Errorf("\nThe connection secret should be in the Cluster's namespace.\ncr.GetWriteConnectionSecretToReference(): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A ResourceSpec defines the desired state of a namespaced managed resource.
+// Unlike the spec of a cluster scoped managed resource it can only refer to a
+// connection secret and a ProviderConfig in the resource's own namespace.
+type ResourceSpec struct {
+	// WriteConnectionSecretToReference specifies the name of a Secret in the
+	// namespace of this managed resource to which any connection details for
+	// this managed resource should be written.
+	// +optional
+	WriteConnectionSecretToReference *xpv1.LocalSecretReference `json:"writeConnectionSecretToRef,omitempty"`
+
+	// PublishConnectionDetailsTo is not supported by namespaced managed
+	// resources, since it refers to a cluster scoped StoreConfig. It is
+	// rejected when webhooks are enabled and ignored otherwise. Use
+	// WriteConnectionSecretToReference instead.
+	// +optional
+	PublishConnectionDetailsTo *xpv1.PublishConnectionDetailsTo `json:"publishConnectionDetailsTo,omitempty"`
+
+	// ProviderConfigReference specifies the ProviderConfig in the namespace of
+	// this managed resource that should be used to manage it.
+	// +kubebuilder:default={"name": "default"}
+	ProviderConfigReference *xpv1.Reference `json:"providerConfigRef,omitempty"`
+
+	// DeletionPolicy specifies what will happen to the underlying external
+	// when this managed resource is deleted - either "Delete" or "Orphan" the
+	// external resource.
+	// +optional
+	// +kubebuilder:default=Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
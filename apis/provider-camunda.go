@@ -21,8 +21,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	clientsv1alpha1 "github.com/crossplane/provider-camunda/apis/client/v1alpha1"
+	clientsv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
 	clusterv1alpha1 "github.com/crossplane/provider-camunda/apis/cluster/v1alpha1"
+	clusterv1beta1 "github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
 	camundav1alpha1 "github.com/crossplane/provider-camunda/apis/v1alpha1"
+	camundav1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
 )
 
 func init() {
@@ -31,6 +35,10 @@ func init() {
 		camundav1alpha1.SchemeBuilder.AddToScheme,
 		clusterv1alpha1.SchemeBuilder.AddToScheme,
 		clientsv1alpha1.SchemeBuilder.AddToScheme,
+		camundav1beta1.SchemeBuilder.AddToScheme,
+		clusterv1beta1.SchemeBuilder.AddToScheme,
+		clientsv1beta1.SchemeBuilder.AddToScheme,
+		namespacedv1beta1.SchemeBuilder.AddToScheme,
 	)
 }
 
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+const errNotProviderConfig = "object is not a v1beta1 ProviderConfig"
+
+// ConvertTo converts this ProviderConfig to the hub version.
+func (pc *ProviderConfig) ConvertTo(h conversion.Hub) error {
+	dst, ok := h.(*v1beta1.ProviderConfig)
+	if !ok {
+		return errors.New(errNotProviderConfig)
+	}
+	pc.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	dst.Spec.Credentials = v1beta1.ProviderCredentials{
+		Source:                    pc.Spec.Credentials.Source,
+		CommonCredentialSelectors: *pc.Spec.Credentials.CommonCredentialSelectors.DeepCopy(),
+	}
+	dst.Spec.ClusterDefaults = nil
+	if d := pc.Spec.ClusterDefaults; d != nil {
+		dst.Spec.ClusterDefaults = &v1beta1.ClusterDefaults{
+			Channel:    d.Channel,
+			Generation: d.Generation,
+			Region:     d.Region,
+			PlanType:   d.PlanType,
+		}
+	}
+
+	pc.Status.ProviderConfigStatus.DeepCopyInto(&dst.Status.ProviderConfigStatus)
+	dst.Status.Health = v1beta1.ProviderConfigHealth{
+		OrganizationID: pc.Status.OrganizationID,
+		Audience:       pc.Status.Audience,
+		TokenExpiry:    pc.Status.TokenExpiry.DeepCopy(),
+		LastCheckTime:  pc.Status.LastCheckTime.DeepCopy(),
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this ProviderConfig.
+func (pc *ProviderConfig) ConvertFrom(h conversion.Hub) error {
+	src, ok := h.(*v1beta1.ProviderConfig)
+	if !ok {
+		return errors.New(errNotProviderConfig)
+	}
+	src.ObjectMeta.DeepCopyInto(&pc.ObjectMeta)
+
+	pc.Spec.Credentials = ProviderCredentials{
+		Source:                    src.Spec.Credentials.Source,
+		CommonCredentialSelectors: *src.Spec.Credentials.CommonCredentialSelectors.DeepCopy(),
+	}
+	pc.Spec.ClusterDefaults = nil
+	if d := src.Spec.ClusterDefaults; d != nil {
+		pc.Spec.ClusterDefaults = &ClusterDefaults{
+			Channel:    d.Channel,
+			Generation: d.Generation,
+			Region:     d.Region,
+			PlanType:   d.PlanType,
+		}
+	}
+
+	src.Status.ProviderConfigStatus.DeepCopyInto(&pc.Status.ProviderConfigStatus)
+	pc.Status.OrganizationID = src.Status.Health.OrganizationID
+	pc.Status.Audience = src.Status.Health.Audience
+	pc.Status.TokenExpiry = src.Status.Health.TokenExpiry.DeepCopy()
+	pc.Status.LastCheckTime = src.Status.Health.LastCheckTime.DeepCopy()
+	return nil
+}
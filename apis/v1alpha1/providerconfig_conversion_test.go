@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+func TestProviderConfigRoundTrip(t *testing.T) {
+	expiry := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	checked := metav1.NewTime(time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC))
+	secretRef := &xpv1.SecretKeySelector{
+		SecretReference: xpv1.SecretReference{Name: "creds", Namespace: "crossplane-system"},
+		Key:             "credentials",
+	}
+
+	spoke := func() *ProviderConfig {
+		pc := &ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: ProviderConfigSpec{
+				Credentials: ProviderCredentials{
+					Source:                    xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef},
+				},
+				ClusterDefaults: &ClusterDefaults{Channel: "channel", Generation: "generation", Region: "region", PlanType: "plan"},
+			},
+			Status: ProviderConfigStatus{
+				OrganizationID: "org",
+				Audience:       "api.cloud.camunda.io",
+				TokenExpiry:    &expiry,
+				LastCheckTime:  &checked,
+			},
+		}
+		pc.Status.Users = 3
+		pc.Status.SetConditions(xpv1.Available())
+		return pc
+	}
+
+	hub := func() *v1beta1.ProviderConfig {
+		pc := &v1beta1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: v1beta1.ProviderConfigSpec{
+				Credentials: v1beta1.ProviderCredentials{
+					Source:                    xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef},
+				},
+				ClusterDefaults: &v1beta1.ClusterDefaults{Channel: "channel", Generation: "generation", Region: "region", PlanType: "plan"},
+			},
+			Status: v1beta1.ProviderConfigStatus{
+				Health: v1beta1.ProviderConfigHealth{
+					OrganizationID: "org",
+					Audience:       "api.cloud.camunda.io",
+					TokenExpiry:    &expiry,
+					LastCheckTime:  &checked,
+				},
+			},
+		}
+		pc.Status.Users = 3
+		pc.Status.SetConditions(xpv1.Available())
+		return pc
+	}
+
+	t.Run("SpokeHubSpoke", func(t *testing.T) {
+		h := &v1beta1.ProviderConfig{}
+		if err := spoke().ConvertTo(h); err != nil {
+			t.Fatalf("ConvertTo(...): %v", err)
+		}
+		if diff := cmp.Diff(hub(), h, test.EquateConditions()); diff != "" {
+			t.Errorf("\nA v1alpha1 ProviderConfig should be converted to v1beta1.\nConvertTo(...): -want, +got:\n%s\n", diff)
+		}
+		got := &ProviderConfig{}
+		if err := got.ConvertFrom(h); err != nil {
+			t.Fatalf("ConvertFrom(...): %v", err)
+		}
+		if diff := cmp.Diff(spoke(), got, test.EquateConditions()); diff != "" {
+			t.Errorf("\nA v1alpha1 ProviderConfig should survive a round trip through v1beta1.\nConvertFrom(ConvertTo(...)): -want, +got:\n%s\n", diff)
+		}
+	})
+
+	t.Run("HubSpokeHub", func(t *testing.T) {
+		s := &ProviderConfig{}
+		if err := s.ConvertFrom(hub()); err != nil {
+			t.Fatalf("ConvertFrom(...): %v", err)
+		}
+		got := &v1beta1.ProviderConfig{}
+		if err := s.ConvertTo(got); err != nil {
+			t.Fatalf("ConvertTo(...): %v", err)
+		}
+		if diff := cmp.Diff(hub(), got, test.EquateConditions()); diff != "" {
+			t.Errorf("\nA v1beta1 ProviderConfig should survive a round trip through v1alpha1.\nConvertTo(ConvertFrom(...)): -want, +got:\n%s\n", diff)
+		}
+	})
+}
@@ -29,6 +29,31 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// ClusterDefaults are the parameters of Clusters that use this
+	// ProviderConfig but leave them empty.
+	// +optional
+	ClusterDefaults *ClusterDefaults `json:"clusterDefaults,omitempty"`
+}
+
+// ClusterDefaults are the default parameters of new Clusters. They are not
+// applied to Clusters that import an existing cluster.
+type ClusterDefaults struct {
+	// Channel is the UUID of the default release channel.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Generation is the UUID of the default generation.
+	// +optional
+	Generation string `json:"generation,omitempty"`
+
+	// Region is the UUID of the default region.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// PlanType is the UUID of the default plan type.
+	// +optional
+	PlanType string `json:"planType,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -43,12 +68,32 @@ type ProviderCredentials struct {
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// OrganizationID is the ID of the Camunda organization the credentials
+	// belong to.
+	// +optional
+	OrganizationID string `json:"organizationID,omitempty"`
+
+	// Audience is the audience the access token was issued for.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// TokenExpiry is the time the last access token expires.
+	// +optional
+	TokenExpiry *metav1.Time `json:"tokenExpiry,omitempty"`
+
+	// LastCheckTime is the last time the credentials were checked against
+	// the Console API.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 
 // A ProviderConfig configures a Camunda provider.
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="ORGANIZATION",type="string",JSONPath=".status.organizationID"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
 // +kubebuilder:resource:scope=Cluster
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A ProviderConfigSpec defines the desired state of a ProviderConfig.
+type ProviderConfigSpec struct {
+	// Credentials required to authenticate to this provider.
+	Credentials ProviderCredentials `json:"credentials"`
+
+	// ClusterDefaults are the parameters of Clusters that use this
+	// ProviderConfig but leave them empty.
+	// +optional
+	ClusterDefaults *ClusterDefaults `json:"clusterDefaults,omitempty"`
+}
+
+// ProviderCredentials required to authenticate.
+type ProviderCredentials struct {
+	// Source of the provider credentials.
+	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem
+	Source xpv1.CredentialsSource `json:"source"`
+
+	xpv1.CommonCredentialSelectors `json:",inline"`
+}
+
+// ClusterDefaults are the default parameters of new Clusters. They are not
+// applied to Clusters that import an existing cluster.
+type ClusterDefaults struct {
+	// Channel is the UUID of the default release channel.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Generation is the UUID of the default generation.
+	// +optional
+	Generation string `json:"generation,omitempty"`
+
+	// Region is the UUID of the default region.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// PlanType is the UUID of the default plan type.
+	// +optional
+	PlanType string `json:"planType,omitempty"`
+}
+
+// A ProviderConfigStatus reflects the observed state of a ProviderConfig.
+type ProviderConfigStatus struct {
+	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// Health is the result of the last check of the credentials against the
+	// Console API.
+	// +optional
+	Health ProviderConfigHealth `json:"health,omitempty"`
+}
+
+// ProviderConfigHealth is the result of a check of the credentials of a
+// ProviderConfig against the Console API.
+type ProviderConfigHealth struct {
+	// OrganizationID is the ID of the Camunda organization the credentials
+	// belong to.
+	// +optional
+	OrganizationID string `json:"organizationID,omitempty"`
+
+	// Audience is the audience the access token was issued for.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// TokenExpiry is the time the last access token expires.
+	// +optional
+	TokenExpiry *metav1.Time `json:"tokenExpiry,omitempty"`
+
+	// LastCheckTime is the last time the credentials were checked against
+	// the Console API.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// ReasonUnauthorized indicates that the credentials of a ProviderConfig were
+// rejected.
+const ReasonUnauthorized xpv1.ConditionReason = "Unauthorized"
+
+// Unauthorized returns a condition that indicates the credentials of a
+// ProviderConfig were rejected.
+func Unauthorized(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnauthorized,
+		Message:            msg,
+	}
+}
+
+// +kubebuilder:object:root=true
+
+// A ProviderConfig configures a Camunda provider.
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="ORGANIZATION",type="string",JSONPath=".status.health.organizationID"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
+// +kubebuilder:resource:scope=Cluster
+type ProviderConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderConfigSpec   `json:"spec"`
+	Status ProviderConfigStatus `json:"status,omitempty"`
+}
+
+// Hub marks this version as the one other versions of ProviderConfig are
+// converted to and from.
+func (*ProviderConfig) Hub() {}
+
+// +kubebuilder:object:root=true
+
+// ProviderConfigList contains a list of ProviderConfig.
+type ProviderConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProviderConfig `json:"items"`
+}
+
+// ProviderConfig type metadata.
+var (
+	ProviderConfigKind             = reflect.TypeOf(ProviderConfig{}).Name()
+	ProviderConfigGroupKind        = schema.GroupKind{Group: Group, Kind: ProviderConfigKind}.String()
+	ProviderConfigKindAPIVersion   = ProviderConfigKind + "." + SchemeGroupVersion.String()
+	ProviderConfigGroupVersionKind = SchemeGroupVersion.WithKind(ProviderConfigKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ProviderConfig{}, &ProviderConfigList{})
+}
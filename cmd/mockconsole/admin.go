@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	console "github.com/sijoma/console-customer-api-go"
+
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+)
+
+// A faultBody is the body of a request to inject a fault.
+type faultBody struct {
+	// Endpoint is the endpoint to inject the fault into, for example
+	// "GET /clusters/{id}". The fault applies to all endpoints if it is empty.
+	Endpoint string `json:"endpoint"`
+
+	// Latency delays the responses, for example "2s".
+	Latency string `json:"latency"`
+
+	// StatusCode replaces the responses with errors of this status.
+	StatusCode int `json:"statusCode"`
+
+	// Count is the number of requests the fault applies to, or zero for all.
+	Count int `json:"count"`
+}
+
+// A statusBody is the body of a request to set the health of a cluster.
+type statusBody struct {
+	// Health is the health of the cluster and all of its components, for
+	// example "Unhealthy".
+	Health string `json:"health"`
+}
+
+// admin serves the endpoints that control a Console:
+//
+//	GET    /admin/state                  returns the clusters and clients
+//	POST   /admin/faults                 injects a fault, see faultBody
+//	DELETE /admin/faults                 removes all faults
+//	PUT    /admin/clusters/{id}/status   sets the health of a cluster, see statusBody
+type admin struct {
+	console *fake.Console
+}
+
+func (a *admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin"), "/"), "/")
+	switch {
+	case len(path) == 1 && path[0] == "state" && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		_ = a.console.Save(w)
+	case len(path) == 1 && path[0] == "faults" && r.Method == http.MethodPost:
+		b := faultBody{}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f := fake.Fault{StatusCode: b.StatusCode, Count: b.Count}
+		if b.Latency != "" {
+			d, err := time.ParseDuration(b.Latency)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f.Latency = d
+		}
+		a.console.Inject(b.Endpoint, f)
+		w.WriteHeader(http.StatusNoContent)
+	case len(path) == 1 && path[0] == "faults" && r.Method == http.MethodDelete:
+		a.console.ClearFaults()
+		w.WriteHeader(http.StatusNoContent)
+	case len(path) == 3 && path[0] == "clusters" && path[2] == "status" && r.Method == http.MethodPut:
+		b := statusBody{}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := a.console.Cluster(path[1]); !ok {
+			http.NotFound(w, r)
+			return
+		}
+		a.console.SetClusterStatus(path[1], fake.Status(console.ClusterHealthStatus(b.Health)))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	console "github.com/sijoma/console-customer-api-go"
+
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+)
+
+func TestAdmin(t *testing.T) {
+	type args struct {
+		method string
+		path   string
+		body   string
+	}
+
+	type want struct {
+		status int
+
+		// listed is whether the response mentions the cluster.
+		listed bool
+
+		// probe is the status of an unauthenticated request to list the
+		// clusters after the admin request, which is Unauthorized unless a
+		// fault is injected into it.
+		probe int
+
+		// health is the health of the cluster after the admin request.
+		health console.ClusterHealthStatus
+	}
+
+	cases := map[string]struct {
+		reason string
+		faults []fake.Fault
+		args   args
+		want   want
+	}{
+		"State": {
+			reason: "The state of the Console should be returned.",
+			args:   args{method: http.MethodGet, path: "/admin/state"},
+			want:   want{status: http.StatusOK, listed: true, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+		"InjectFault": {
+			reason: "An injected fault should apply to its endpoint.",
+			args:   args{method: http.MethodPost, path: "/admin/faults", body: `{"endpoint": "GET /clusters", "statusCode": 503}`},
+			want:   want{status: http.StatusNoContent, probe: http.StatusServiceUnavailable, health: console.HEALTHY},
+		},
+		"InjectFaultOtherEndpoint": {
+			reason: "An injected fault should not apply to other endpoints.",
+			args:   args{method: http.MethodPost, path: "/admin/faults", body: `{"endpoint": "GET /clusters/{id}", "statusCode": 503}`},
+			want:   want{status: http.StatusNoContent, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+		"InjectLatency": {
+			reason: "A fault with a latency should only delay responses.",
+			args:   args{method: http.MethodPost, path: "/admin/faults", body: `{"latency": "1ms"}`},
+			want:   want{status: http.StatusNoContent, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+		"InvalidLatency": {
+			reason: "A fault with a malformed latency should be rejected.",
+			args:   args{method: http.MethodPost, path: "/admin/faults", body: `{"latency": "soon", "statusCode": 503}`},
+			want:   want{status: http.StatusBadRequest, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+		"MalformedFault": {
+			reason: "A malformed fault should be rejected.",
+			args:   args{method: http.MethodPost, path: "/admin/faults", body: `{`},
+			want:   want{status: http.StatusBadRequest, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+		"ClearFaults": {
+			reason: "Clearing the faults should remove all injected faults.",
+			faults: []fake.Fault{{StatusCode: http.StatusServiceUnavailable}},
+			args:   args{method: http.MethodDelete, path: "/admin/faults"},
+			want:   want{status: http.StatusNoContent, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+		"SetClusterStatus": {
+			reason: "The health of a cluster should be set.",
+			args:   args{method: http.MethodPut, path: "/admin/clusters/cool-id/status", body: `{"health": "Unhealthy"}`},
+			want:   want{status: http.StatusNoContent, probe: http.StatusUnauthorized, health: console.UNHEALTHY},
+		},
+		"SetUnknownClusterStatus": {
+			reason: "Setting the health of a cluster that does not exist should return NotFound.",
+			args:   args{method: http.MethodPut, path: "/admin/clusters/unknown-id/status", body: `{"health": "Unhealthy"}`},
+			want:   want{status: http.StatusNotFound, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+		"UnknownEndpoint": {
+			reason: "Requests to unknown admin endpoints should return NotFound.",
+			args:   args{method: http.MethodGet, path: "/admin/faults"},
+			want:   want{status: http.StatusNotFound, probe: http.StatusUnauthorized, health: console.HEALTHY},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewConsole()
+			c.AddCluster(console.Cluster{Uuid: "cool-id", Name: "cool-cluster", Status: fake.HealthyStatus()})
+			for _, f := range tc.faults {
+				c.Inject("", f)
+			}
+
+			rec := httptest.NewRecorder()
+			(&admin{console: c}).ServeHTTP(rec, httptest.NewRequest(tc.args.method, tc.args.path, strings.NewReader(tc.args.body)))
+
+			probe := httptest.NewRecorder()
+			c.ServeHTTP(probe, httptest.NewRequest(http.MethodGet, "/clusters", nil))
+
+			cl, _ := c.Cluster("cool-id")
+			got := want{status: rec.Code, listed: strings.Contains(rec.Body.String(), "cool-id"), probe: probe.Code, health: cl.Status.Ready}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nadmin.ServeHTTP(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
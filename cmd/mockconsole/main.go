@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// mockconsole serves a mock of the Camunda Console API and its authorization
+// server, so that the provider can be tried without a Camunda SaaS
+// organization.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+)
+
+func main() {
+	var (
+		app          = kingpin.New(filepath.Base(os.Args[0]), "A mock of the Camunda Console API for local development.").DefaultEnvars()
+		listen       = app.Flag("listen", "The address to serve the mock Console API on.").Default(":8090").String()
+		url          = app.Flag("url", "The URL the provider reaches the mock Console API at. Defaults to http://localhost and the port of --listen.").String()
+		provisioning = app.Flag("provisioning", "How long new clusters are Creating before they become Healthy.").Default("30s").Duration()
+		stateFile    = app.Flag("state-file", "A file to load clusters and clients from on start, and to save them to after every change. State is kept in memory only when it is not set.").String()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	c := fake.NewConsole(fake.WithProvisioning(*provisioning))
+	if *stateFile != "" {
+		kingpin.FatalIfError(load(c, *stateFile), "Cannot load state")
+	}
+	if *url == "" {
+		*url = "http://localhost:" + (*listen)[strings.LastIndex(*listen, ":")+1:]
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/admin/", &admin{console: c})
+	mux.Handle("/", persist(c, *stateFile))
+	srv := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	fmt.Printf("Serving the mock Console API at %s. Use these ProviderConfig credentials:\n%s\n", *url, c.CredentialsFor(*url))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		kingpin.FatalIfError(err, "Cannot serve mock Console API")
+	}
+}
+
+// persist returns a handler that serves the supplied Console and saves its
+// state to the supplied file after every request that may change it.
+func persist(c *fake.Console, file string) http.Handler {
+	if file == "" {
+		return c
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.ServeHTTP(w, r)
+		if r.Method == http.MethodGet || r.URL.Path == fake.TokenPath {
+			return
+		}
+		if err := save(c, file); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot save state: %v\n", err)
+		}
+	})
+}
+
+// load loads the state of the supplied Console from the supplied file, unless
+// the file does not exist yet.
+func load(c *fake.Console, file string) error {
+	f, err := os.Open(filepath.Clean(file))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // Nothing was written to the file.
+	return c.Load(f)
+}
+
+// save saves the state of the supplied Console to the supplied file. The file
+// is replaced at once, so that it is never left half written.
+func save(c *fake.Console, file string) error {
+	tmp := file + ".tmp"
+	f, err := os.Create(filepath.Clean(tmp))
+	if err != nil {
+		return err
+	}
+	if err := c.Save(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
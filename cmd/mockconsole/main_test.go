@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	console "github.com/sijoma/console-customer-api-go"
+
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+)
+
+// token returns an access token of the supplied Console.
+func token(t *testing.T, c *fake.Console) string {
+	t.Helper()
+	form := url.Values{"client_id": {fake.ClientID}, "client_secret": {fake.ClientSecret}}
+	req := httptest.NewRequest(http.MethodPost, fake.TokenPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	tok := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(rec.Body).Decode(&tok); err != nil {
+		t.Fatalf("cannot get access token: %v", err)
+	}
+	return tok.AccessToken
+}
+
+func TestPersist(t *testing.T) {
+	type want struct {
+		// saved is whether the state file was replaced.
+		saved bool
+
+		// exists is whether the cluster exists in the state file.
+		exists bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		method string
+		path   string
+		want   want
+	}{
+		"Change": {
+			reason: "The state should be saved after a request that changes it.",
+			method: http.MethodDelete,
+			path:   "/clusters/cool-id",
+			want:   want{saved: true, exists: false},
+		},
+		"Read": {
+			reason: "The state should not be saved after a request that only reads it.",
+			method: http.MethodGet,
+			path:   "/clusters",
+			want:   want{saved: false},
+		},
+		"Token": {
+			reason: "The state should not be saved after a token request.",
+			method: http.MethodPost,
+			path:   fake.TokenPath,
+			want:   want{saved: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "state.json")
+
+			// The state file starts out with a stale cluster, so that it can
+			// be told whether it was replaced.
+			stale := fake.NewConsole()
+			stale.AddCluster(console.Cluster{Uuid: "stale-id"})
+			if err := save(stale, file); err != nil {
+				t.Fatalf("save(...): unexpected error: %v", err)
+			}
+
+			c := fake.NewConsole()
+			c.AddCluster(console.Cluster{Uuid: "cool-id"})
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token(t, c))
+			persist(c, file).ServeHTTP(httptest.NewRecorder(), req)
+
+			loaded := fake.NewConsole()
+			if err := load(loaded, file); err != nil {
+				t.Fatalf("load(...): unexpected error: %v", err)
+			}
+			_, staleExists := loaded.Cluster("stale-id")
+			_, exists := loaded.Cluster("cool-id")
+			got := want{saved: !staleExists, exists: exists}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\npersist(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	type want struct {
+		err    bool
+		exists bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		state  string
+		want   want
+	}{
+		"NoStateFile": {
+			reason: "A Console should start out empty if there is no state file yet.",
+			want:   want{exists: false},
+		},
+		"Saved": {
+			reason: "The clusters of the state file should be loaded.",
+			state:  `{"clusters": [{"uuid": "cool-id", "name": "cool-cluster"}]}`,
+			want:   want{exists: true},
+		},
+		"Malformed": {
+			reason: "A malformed state file should return an error.",
+			state:  `{`,
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "state.json")
+			if tc.state != "" {
+				if err := os.WriteFile(file, []byte(tc.state), 0o600); err != nil {
+					t.Fatalf("os.WriteFile(...): unexpected error: %v", err)
+				}
+			}
+
+			c := fake.NewConsole()
+			err := load(c, file)
+			_, exists := c.Cluster("cool-id")
+			got := want{err: err != nil, exists: exists}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nload(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
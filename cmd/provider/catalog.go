@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/controller/catalog"
+)
+
+const (
+	outputTable = "table"
+	outputYAML  = "yaml"
+)
+
+// A catalogCommand prints the cluster parameters the Console API offers.
+type catalogCommand struct {
+	*kingpin.CmdClause
+
+	credentials *string
+	output      *string
+}
+
+func newCatalogCommand(app *kingpin.Application) *catalogCommand {
+	c := &catalogCommand{CmdClause: app.Command("catalog", "Print the channels, generations, regions and plan types the Console API offers, to write Clusters with.")}
+	c.credentials = c.Flag("credentials", "A file with the JSON credentials of a ProviderConfig secret.").Short('c').Required().String()
+	c.output = c.Flag("output", "The output format.").Short('o').Default(outputTable).Enum(outputTable, outputYAML)
+	return c
+}
+
+// Run prints the cluster parameters the Console API offers to the credentials.
+func (c *catalogCommand) Run(ctx context.Context, w io.Writer) error {
+	creds, err := os.ReadFile(filepath.Clean(*c.credentials))
+	if err != nil {
+		return errors.Wrap(err, "cannot read credentials")
+	}
+	svc, err := camunda.Authenticate(ctx, creds)
+	if err != nil {
+		return errors.Wrap(err, "cannot authenticate")
+	}
+	p, err := svc.GetParameters(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get cluster parameters")
+	}
+
+	if *c.output == outputYAML {
+		b, err := yaml.Marshal(catalog.Observation(p))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+	return printCatalog(w, p)
+}
+
+// printCatalog prints the supplied cluster parameters as a table.
+func printCatalog(w io.Writer, p *camunda.Parameters) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tID\tNAME")
+	for _, ch := range p.Channels {
+		fmt.Fprintf(tw, "channel\t%s\t%s\n", ch.ID, ch.Name)
+		for _, g := range ch.Generations {
+			of := ch.Name
+			if g.ID == ch.DefaultGeneration.ID {
+				of += ", default"
+			}
+			fmt.Fprintf(tw, "generation\t%s\t%s (%s)\n", g.ID, g.Name, of)
+		}
+	}
+	for _, r := range p.Regions {
+		fmt.Fprintf(tw, "region\t%s\t%s\n", r.ID, r.Name)
+	}
+	for _, pt := range p.PlanTypes {
+		fmt.Fprintf(tw, "planType\t%s\t%s\n", pt.ID, pt.Name)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+)
+
+func TestCatalogCommand(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		output string
+		want   string
+	}{
+		"Table": {
+			reason: "The cluster parameters should be printed as a table by default.",
+			want: `TYPE        ID                                    NAME
+channel     6bdcf0e5-ff1e-4f7c-a5a3-0b6a1e7f2b10  Stable
+generation  0b3c7a43-1c8e-4d5b-9fcd-3f3c3d0e8a21  Zeebe 8.2.2 (Stable, default)
+region      2f6c1d8e-9b7a-4c3e-8d2f-5a4b3c2d1e01  Fake Region
+planType    7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d  Trial Package
+`,
+		},
+		"YAML": {
+			reason: "The cluster parameters should be printed like the status of a ClusterCatalog.",
+			output: "yaml",
+			want: `channels:
+- defaultGeneration:
+    id: 0b3c7a43-1c8e-4d5b-9fcd-3f3c3d0e8a21
+    name: Zeebe 8.2.2
+  generations:
+  - id: 0b3c7a43-1c8e-4d5b-9fcd-3f3c3d0e8a21
+    name: Zeebe 8.2.2
+  id: 6bdcf0e5-ff1e-4f7c-a5a3-0b6a1e7f2b10
+  name: Stable
+planTypes:
+- id: 7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d
+  name: Trial Package
+regions:
+- id: 2f6c1d8e-9b7a-4c3e-8d2f-5a4b3c2d1e01
+  name: Fake Region
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := fake.NewServer()
+			defer srv.Close()
+			creds := filepath.Join(t.TempDir(), "credentials.json")
+			if err := os.WriteFile(creds, srv.Credentials(), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			app := kingpin.New("provider", "")
+			c := newCatalogCommand(app)
+			args := []string{"catalog", "--credentials", creds}
+			if tc.output != "" {
+				args = append(args, "--output", tc.output)
+			}
+			if _, err := app.Parse(args); err != nil {
+				t.Fatalf("app.Parse(...): %v", err)
+			}
+
+			got := &bytes.Buffer{}
+			if err := c.Run(context.Background(), got); err != nil {
+				t.Fatalf("c.Run(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got.String()); diff != "" {
+				t.Errorf("\n%s\nc.Run(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	clusterv1beta1 "github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+)
+
+// invalidNameChars are the characters that may not appear in object names.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// An importCommand prints manifests that import the clusters and clients of
+// an organization.
+type importCommand struct {
+	*kingpin.CmdClause
+
+	credentials    *string
+	providerConfig *string
+	deletionPolicy *string
+}
+
+func newImportCommand(app *kingpin.Application) *importCommand {
+	c := &importCommand{CmdClause: app.Command("import", "Print Cluster and Client manifests that import all clusters and clients of the organization.")}
+	c.credentials = c.Flag("credentials", "A file with the JSON credentials of a ProviderConfig secret.").Short('c').Required().String()
+	c.providerConfig = c.Flag("provider-config", "The name of the ProviderConfig the imported resources use.").Default("default").String()
+	c.deletionPolicy = c.Flag("deletion-policy", "The deletion policy of the imported resources. Orphan keeps the clusters and clients when the resources are deleted.").
+		Default(string(xpv1.DeletionOrphan)).Enum(string(xpv1.DeletionOrphan), string(xpv1.DeletionDelete))
+	return c
+}
+
+// Run prints the manifests of all clusters and clients the credentials have
+// access to.
+func (c *importCommand) Run(ctx context.Context, w io.Writer) error {
+	creds, err := os.ReadFile(filepath.Clean(*c.credentials))
+	if err != nil {
+		return errors.Wrap(err, "cannot read credentials")
+	}
+	svc, err := camunda.Authenticate(ctx, creds)
+	if err != nil {
+		return errors.Wrap(err, "cannot authenticate")
+	}
+	clusters, err := svc.ListClusters(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot list clusters")
+	}
+	clients := map[string][]camunda.Client{}
+	for _, cl := range clusters {
+		l, err := svc.ListClients(ctx, cl.ID)
+		if err != nil {
+			return errors.Wrapf(err, "cannot list clients of cluster %s", cl.ID)
+		}
+		clients[cl.ID] = l
+	}
+
+	im := &importer{
+		providerConfig: *c.providerConfig,
+		deletionPolicy: xpv1.DeletionPolicy(*c.deletionPolicy),
+	}
+	for _, m := range im.manifests(clusters, clients) {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A manifest is an object to print, with comments about it.
+type manifest struct {
+	comments []string
+	object   any
+}
+
+// write prints the manifest as a YAML document. The status and the empty
+// creation timestamp are left out, since they are not part of the manifest.
+func (m manifest) write(w io.Writer) error {
+	b, err := json.Marshal(m.object)
+	if err != nil {
+		return err
+	}
+	obj := map[string]any{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	delete(obj, "status")
+	if md, ok := obj["metadata"].(map[string]any); ok {
+		delete(md, "creationTimestamp")
+	}
+	y, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	doc := &strings.Builder{}
+	doc.WriteString("---\n")
+	for _, c := range m.comments {
+		doc.WriteString("# " + c + "\n")
+	}
+	doc.Write(y)
+	_, err = io.WriteString(w, doc.String())
+	return err
+}
+
+// An importer builds the manifests that import clusters and clients.
+type importer struct {
+	providerConfig string
+	deletionPolicy xpv1.DeletionPolicy
+}
+
+// manifests returns a Cluster for each supplied cluster, followed by a Client
+// for each of its clients. Clusters are sorted by name, clients by name within
+// their cluster.
+func (im *importer) manifests(clusters []camunda.Cluster, clients map[string][]camunda.Client) []manifest {
+	clusters = append([]camunda.Cluster(nil), clusters...)
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+
+	clusterNames, clientNames := map[string]bool{}, map[string]bool{}
+	var out []manifest
+	for _, cl := range clusters {
+		name, comments := objectName(clusterv1beta1.ClusterKind, cl.Name, cl.ID, clusterNames)
+		cr := &clusterv1beta1.Cluster{
+			TypeMeta:   metav1.TypeMeta{APIVersion: clusterv1beta1.SchemeGroupVersion.String(), Kind: clusterv1beta1.ClusterKind},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: clusterv1beta1.ClusterSpec{
+				ResourceSpec: im.resourceSpec(),
+				ForProvider: clusterv1beta1.ClusterParameters{
+					Channel:    cl.Channel,
+					Generation: cl.Generation,
+					Region:     cl.Region,
+					PlanType:   cl.PlanType,
+				},
+			},
+		}
+		meta.SetExternalName(cr, cl.ID)
+		out = append(out, manifest{comments: comments, object: cr})
+
+		l := append([]camunda.Client(nil), clients[cl.ID]...)
+		sort.SliceStable(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+		for _, c := range l {
+			name, comments := objectName(clientv1beta1.ClientKind, c.Name, c.ID, clientNames)
+			comments = append(comments, "The Console API does not return the secret of an existing client, so the connection secret has no ZEEBE_CLIENT_SECRET.")
+			cr := &clientv1beta1.Client{
+				TypeMeta:   metav1.TypeMeta{APIVersion: clientv1beta1.SchemeGroupVersion.String(), Kind: clientv1beta1.ClientKind},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: clientv1beta1.ClientSpec{
+					ResourceSpec: im.resourceSpec(),
+					ForProvider:  clientv1beta1.ClientParameters{ClusterID: cl.ID},
+				},
+			}
+			meta.SetExternalName(cr, c.ID)
+			out = append(out, manifest{comments: comments, object: cr})
+		}
+	}
+	return out
+}
+
+func (im *importer) resourceSpec() xpv1.ResourceSpec {
+	return xpv1.ResourceSpec{
+		ProviderConfigReference: &xpv1.Reference{Name: im.providerConfig},
+		DeletionPolicy:          im.deletionPolicy,
+	}
+}
+
+// objectName returns the name of the object that imports the supplied
+// external resource. The object is named like the external resource, unless
+// that is not a valid or unique object name. The provider identifies the
+// external resource by its external-name, so a different name does not keep
+// the object from becoming ready. The returned comments explain any change.
+func objectName(kind, name, id string, taken map[string]bool) (string, []string) {
+	n := name
+	if len(validation.IsDNS1123Subdomain(n)) > 0 {
+		n = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(n), "-"), "-.")
+		if len(n) > validation.DNS1123SubdomainMaxLength {
+			n = strings.Trim(n[:validation.DNS1123SubdomainMaxLength], "-.")
+		}
+	}
+	if n == "" || taken[n] {
+		n = strings.Trim(strings.Join([]string{n, id}, "-"), "-")
+	}
+	taken[n] = true
+	if n == name {
+		return n, nil
+	}
+	return n, []string{fmt.Sprintf("%q is not a valid or unique %s name. The %s keeps its name in the Console.", name, kind, strings.ToLower(kind))}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	console "github.com/sijoma/console-customer-api-go"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+)
+
+func TestImportManifests(t *testing.T) {
+	spec := camunda.ClusterSpec{Channel: "channel", Generation: "generation", Region: "region", PlanType: "plan"}
+	clusters := []camunda.Cluster{
+		{ID: "4a2f", ClusterSpec: withName(spec, "Production")},
+		{ID: "0b1c", ClusterSpec: withName(spec, "dev")},
+	}
+	clients := map[string][]camunda.Client{
+		"0b1c": {{ID: "dev-worker", Name: "worker"}},
+		"4a2f": {{ID: "prod-worker", Name: "worker"}},
+	}
+
+	want := `---
+# "Production" is not a valid or unique Cluster name. The cluster keeps its name in the Console.
+apiVersion: camunda.crossplane.io/v1beta1
+kind: Cluster
+metadata:
+  annotations:
+    crossplane.io/external-name: 4a2f
+  name: production
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    channel: channel
+    generation: generation
+    planType: plan
+    region: region
+  providerConfigRef:
+    name: default
+---
+# The Console API does not return the secret of an existing client, so the connection secret has no ZEEBE_CLIENT_SECRET.
+apiVersion: camunda.crossplane.io/v1beta1
+kind: Client
+metadata:
+  annotations:
+    crossplane.io/external-name: prod-worker
+  name: worker
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    clusterID: 4a2f
+  providerConfigRef:
+    name: default
+---
+apiVersion: camunda.crossplane.io/v1beta1
+kind: Cluster
+metadata:
+  annotations:
+    crossplane.io/external-name: 0b1c
+  name: dev
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    channel: channel
+    generation: generation
+    planType: plan
+    region: region
+  providerConfigRef:
+    name: default
+---
+# "worker" is not a valid or unique Client name. The client keeps its name in the Console.
+# The Console API does not return the secret of an existing client, so the connection secret has no ZEEBE_CLIENT_SECRET.
+apiVersion: camunda.crossplane.io/v1beta1
+kind: Client
+metadata:
+  annotations:
+    crossplane.io/external-name: dev-worker
+  name: worker-dev-worker
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    clusterID: 0b1c
+  providerConfigRef:
+    name: default
+`
+
+	im := &importer{providerConfig: "default", deletionPolicy: xpv1.DeletionOrphan}
+	got := &strings.Builder{}
+	for _, m := range im.manifests(clusters, clients) {
+		if err := m.write(got); err != nil {
+			t.Fatalf("m.write(...): %v", err)
+		}
+	}
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("\nClusters and their clients should be imported by their IDs, named like in the Console where possible.\nim.manifests(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestImportCommand(t *testing.T) {
+	srv := fake.NewServer(fake.WithClusters(console.Cluster{Uuid: "2611e047-74ab-47ba-aae4-115be2918fbe", Name: "cool-cluster"}))
+	defer srv.Close()
+	worker := srv.AddClient("2611e047-74ab-47ba-aae4-115be2918fbe", "worker")
+	creds := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(creds, srv.Credentials(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	app := kingpin.New("provider", "")
+	c := newImportCommand(app)
+	if _, err := app.Parse([]string{"import", "--credentials", creds, "--provider-config", "example", "--deletion-policy", "Delete"}); err != nil {
+		t.Fatalf("app.Parse(...): %v", err)
+	}
+	out := &strings.Builder{}
+	if err := c.Run(context.Background(), out); err != nil {
+		t.Fatalf("c.Run(...): %v", err)
+	}
+
+	docs := strings.Split(out.String(), "---\n")[1:]
+	if len(docs) != 2 {
+		t.Fatalf("\nA Cluster and a Client should be printed.\nc.Run(...): got %d documents:\n%s", len(docs), out)
+	}
+	got := &clientv1beta1.Client{}
+	if err := yaml.UnmarshalStrict([]byte(docs[1]), got); err != nil {
+		t.Fatalf("yaml.UnmarshalStrict(...): %v", err)
+	}
+	want := map[string]string{
+		"name":           "worker",
+		"external-name":  worker.ClientId,
+		"clusterID":      "2611e047-74ab-47ba-aae4-115be2918fbe",
+		"providerConfig": "example",
+		"deletionPolicy": "Delete",
+	}
+	if diff := cmp.Diff(want, map[string]string{
+		"name":           got.GetName(),
+		"external-name":  got.GetAnnotations()["crossplane.io/external-name"],
+		"clusterID":      got.Spec.ForProvider.ClusterID,
+		"providerConfig": got.Spec.ProviderConfigReference.Name,
+		"deletionPolicy": string(got.Spec.DeletionPolicy),
+	}); diff != "" {
+		t.Errorf("\nThe clients of the organization should be imported with the flags' settings.\nc.Run(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func withName(s camunda.ClusterSpec, name string) camunda.ClusterSpec {
+	s.Name = name
+	return s
+}
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -39,23 +40,49 @@ import (
 	"github.com/crossplane/provider-camunda/apis"
 	"github.com/crossplane/provider-camunda/apis/v1alpha1"
 	camunda "github.com/crossplane/provider-camunda/internal/controller"
+	"github.com/crossplane/provider-camunda/internal/controller/config"
 	"github.com/crossplane/provider-camunda/internal/controller/features"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+	"github.com/crossplane/provider-camunda/internal/tracing"
+	"github.com/crossplane/provider-camunda/internal/webhook"
 )
 
 func main() {
 	var (
 		app            = kingpin.New(filepath.Base(os.Args[0]), "Camunda support for Crossplane.").DefaultEnvars()
-		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
-		leaderElection = app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		run            = app.Command("run", "Run the controllers. This is the default command.").Default()
+		debug          = run.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		leaderElection = run.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
 
-		syncInterval     = app.Flag("sync", "How often all resources will be double-checked for drift from the desired state.").Short('s').Default("1h").Duration()
-		pollInterval     = app.Flag("poll", "How often individual resources will be checked for drift from the desired state").Default("1m").Duration()
-		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
+		syncInterval     = run.Flag("sync", "How often all resources will be double-checked for drift from the desired state.").Short('s').Default("1h").Duration()
+		pollInterval     = run.Flag("poll", "How often individual resources will be checked for drift from the desired state").Default("1m").Duration()
+		pollBusy         = run.Flag("poll-busy", "How often individual resources that are being created, updated or deleted will be checked for drift from the desired state.").Default("10s").Duration()
+		pollIdle         = run.Flag("poll-idle", "How often individual resources that are healthy and idle will be checked for drift from the desired state.").Default("10m").Duration()
+		maxReconcileRate = run.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
 
-		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
-		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
+		namespace                  = run.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
+		enableExternalSecretStores = run.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
+
+		metricsBindAddress     = run.Flag("metrics-bind-address", "The address the metrics endpoint binds to.").Default(":8080").Envar("METRICS_BIND_ADDRESS").String()
+		healthProbeBindAddress = run.Flag("health-probe-bind-address", "The address the health and readiness probe endpoints bind to.").Default(":8081").Envar("HEALTH_PROBE_BIND_ADDRESS").String()
+
+		webhookTLSCertDir       = run.Flag("webhook-tls-cert-dir", "The directory of TLS certificate that will be used by the webhook server. Webhooks are disabled when it is not set.").Envar("WEBHOOK_TLS_CERT_DIR").String()
+		enableCatalogValidation = run.Flag("enable-catalog-validation", "Check the parameters of Clusters against the Console API's parameters catalog when they are admitted. Requires webhooks.").Default("false").Envar("ENABLE_CATALOG_VALIDATION").Bool()
+
+		otlpEndpoint     = run.Flag("otlp-endpoint", "The host and port of the OTLP/HTTP collector traces are exported to. Tracing is disabled when it is not set.").Envar("OTLP_ENDPOINT").String()
+		otlpInsecure     = run.Flag("otlp-insecure", "Connect to the OTLP/HTTP collector without TLS.").Default("false").Envar("OTLP_INSECURE").Bool()
+		traceSampleRatio = run.Flag("trace-sample-ratio", "The fraction of reconciles that are traced.").Default("1").Envar("TRACE_SAMPLE_RATIO").Float64()
 	)
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	catalogCmd := newCatalogCommand(app)
+	importCmd := newImportCommand(app)
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case catalogCmd.FullCommand():
+		kingpin.FatalIfError(catalogCmd.Run(context.Background(), os.Stdout), "Cannot print cluster parameters")
+		return
+	case importCmd.FullCommand():
+		kingpin.FatalIfError(importCmd.Run(context.Background(), os.Stdout), "Cannot print import manifests")
+		return
+	}
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	log := logging.NewLogrLogger(zl.WithName("provider-camunda"))
@@ -66,6 +93,14 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Endpoint:    *otlpEndpoint,
+		Insecure:    *otlpInsecure,
+		SampleRatio: *traceSampleRatio,
+	})
+	kingpin.FatalIfError(err, "Cannot setup tracing")
+	defer shutdownTracing(context.Background()) //nolint:errcheck // There's nothing left to do if flushing traces fails.
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -84,9 +119,19 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		CertDir: *webhookTLSCertDir,
+
+		MetricsBindAddress:     *metricsBindAddress,
+		HealthProbeBindAddress: *healthProbeBindAddress,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Camunda APIs to scheme")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("ping", healthz.Ping), "Cannot add health check")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("informers", config.CachesSynced(mgr.GetCache().WaitForCacheSync)), "Cannot add informer readiness check")
+	// Each check is also served on its own path, so that the readiness probe of
+	// a pod that serves webhooks can leave out the Console API check.
+	kingpin.FatalIfError(mgr.AddReadyzCheck("console", config.ConsoleReachable(mgr.GetClient())), "Cannot add Console API readiness check")
 
 	o := controller.Options{
 		Logger:                  log,
@@ -115,6 +160,14 @@ func main() {
 		})), "cannot create default store config")
 	}
 
-	kingpin.FatalIfError(camunda.Setup(mgr, o), "Cannot setup Camunda controllers")
+	if *enableCatalogValidation {
+		o.Features.Enable(features.EnableCatalogValidation)
+		log.Info("Feature enabled", "flag", features.EnableCatalogValidation)
+	}
+
+	kingpin.FatalIfError(camunda.Setup(mgr, o, poll.Intervals{Busy: *pollBusy, Idle: *pollIdle}), "Cannot setup Camunda controllers")
+	if *webhookTLSCertDir != "" {
+		kingpin.FatalIfError(webhook.Setup(mgr, o), "Cannot setup Camunda webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
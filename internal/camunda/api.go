@@ -0,0 +1,380 @@
+package camunda
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	console "github.com/sijoma/console-customer-api-go"
+)
+
+// A ClusterAPI manages the clusters of an organization.
+type ClusterAPI interface {
+	// ListClusters returns all clusters.
+	ListClusters(ctx context.Context) ([]Cluster, error)
+
+	// GetCluster returns the cluster with the supplied ID, or a NotFoundError.
+	GetCluster(ctx context.Context, id string) (*Cluster, error)
+
+	// CreateCluster creates a cluster and returns its ID.
+	CreateCluster(ctx context.Context, s ClusterSpec) (string, error)
+
+	// DeleteCluster deletes the cluster with the supplied ID, or returns a
+	// NotFoundError.
+	DeleteCluster(ctx context.Context, id string) error
+}
+
+// A ClientAPI manages the API clients of clusters.
+type ClientAPI interface {
+	// ListClients returns the clients of the supplied cluster, or a
+	// NotFoundError if the cluster does not exist.
+	ListClients(ctx context.Context, clusterID string) ([]Client, error)
+
+	// GetClient returns the connection details of a client of the supplied
+	// cluster, or a NotFoundError.
+	GetClient(ctx context.Context, clusterID, clientID string) (*ClientDetails, error)
+
+	// CreateClient creates a client of the supplied cluster and returns its
+	// credentials. The client secret is only ever returned on creation.
+	CreateClient(ctx context.Context, clusterID, name string) (*ClientCredentials, error)
+
+	// DeleteClient deletes a client of the supplied cluster, or returns a
+	// NotFoundError.
+	DeleteClient(ctx context.Context, clusterID, clientID string) error
+}
+
+// A ParametersAPI returns the parameters new clusters may use.
+type ParametersAPI interface {
+	GetParameters(ctx context.Context) (*Parameters, error)
+}
+
+// An API is the part of the Console API used by the provider.
+type API interface {
+	ClusterAPI
+	ClientAPI
+	ParametersAPI
+}
+
+// A HealthStatus is the health of a cluster or of one of its components.
+type HealthStatus string
+
+// Health statuses reported by the Console API.
+const (
+	Healthy   HealthStatus = HealthStatus(console.HEALTHY)
+	Unhealthy HealthStatus = HealthStatus(console.UNHEALTHY)
+	Creating  HealthStatus = HealthStatus(console.CREATING)
+	Updating  HealthStatus = HealthStatus(console.UPDATING)
+)
+
+// A ClusterStatus is the health of a cluster and of its components. The
+// health of a component is empty if the component does not report one.
+type ClusterStatus struct {
+	Ready    HealthStatus
+	Zeebe    HealthStatus
+	Operate  HealthStatus
+	Tasklist HealthStatus
+	Optimize HealthStatus
+}
+
+// ClusterEndpoints are the addresses of the components of a cluster.
+type ClusterEndpoints struct {
+	Zeebe    string
+	Operate  string
+	Tasklist string
+	Optimize string
+}
+
+// A ClusterSpec describes a cluster to create.
+type ClusterSpec struct {
+	Name       string
+	Channel    string
+	Generation string
+	Region     string
+	PlanType   string
+}
+
+// A Cluster is a Camunda Platform 8 cluster.
+type Cluster struct {
+	ID string
+	ClusterSpec
+
+	Status    ClusterStatus
+	Endpoints ClusterEndpoints
+}
+
+// A Client is an API client of a cluster.
+type Client struct {
+	ID   string
+	Name string
+}
+
+// ClientDetails are what an application needs to connect to a cluster as a
+// client, except for the client secret.
+type ClientDetails struct {
+	Client
+
+	ZeebeAddress                string
+	ZeebeAuthorizationServerURL string
+}
+
+// ClientCredentials are the credentials of a new client.
+type ClientCredentials struct {
+	ID     string
+	Secret string
+}
+
+// A Parameter is one of the values a parameter of a cluster may take.
+type Parameter struct {
+	ID   string
+	Name string
+}
+
+// A Channel is a release channel of clusters.
+type Channel struct {
+	Parameter
+
+	// Generations are the generations clusters of this channel may use.
+	Generations       []Parameter
+	DefaultGeneration Parameter
+}
+
+// Parameters are the values the parameters of new clusters may take.
+type Parameters struct {
+	Channels  []Channel
+	Regions   []Parameter
+	PlanTypes []Parameter
+}
+
+// A NotFoundError indicates that the requested resource does not exist.
+type NotFoundError struct {
+	Err error
+}
+
+func (e NotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound returns true if the supplied error is a NotFoundError.
+func IsNotFound(err error) bool {
+	nf := NotFoundError{}
+	return errors.As(err, &nf)
+}
+
+// apiError returns a typed error for the supplied error of a Console API call,
+// depending on the status of the supplied response.
+func apiError(resp *http.Response, err error) error {
+	if err == nil {
+		return nil
+	}
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return NotFoundError{err}
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return UnauthorizedError{err}
+		}
+	}
+	return err
+}
+
+// withToken returns a context that authenticates Console API calls with a
+// valid access token of the Service.
+func (s *Service) withToken(ctx context.Context) (context.Context, error) {
+	t, err := s.Token()
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, console.ContextAccessToken, t.AccessToken), nil
+}
+
+// ListClusters returns all clusters.
+func (s *Service) ListClusters(ctx context.Context) ([]Cluster, error) {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l, resp, err := s.ClustersApi.GetClusters(ctx).Execute()
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	clusters := make([]Cluster, len(l))
+	for i := range l {
+		clusters[i] = cluster(&l[i])
+	}
+	return clusters, nil
+}
+
+// GetCluster returns the cluster with the supplied ID.
+func (s *Service) GetCluster(ctx context.Context, id string) (*Cluster, error) {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, resp, err := s.ClustersApi.GetCluster(ctx, id).Execute()
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	cl := cluster(c)
+	return &cl, nil
+}
+
+// CreateCluster creates a cluster and returns its ID.
+func (s *Service) CreateCluster(ctx context.Context, cs ClusterSpec) (string, error) {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c, resp, err := s.ClustersApi.CreateCluster(ctx).
+		CreateClusterBody(console.CreateClusterBody{
+			Name:         cs.Name,
+			ChannelId:    cs.Channel,
+			GenerationId: cs.Generation,
+			RegionId:     cs.Region,
+			PlanTypeId:   cs.PlanType,
+		}).
+		Execute()
+	if err != nil {
+		return "", apiError(resp, err)
+	}
+	return c.GetClusterId(), nil
+}
+
+// DeleteCluster deletes the cluster with the supplied ID.
+func (s *Service) DeleteCluster(ctx context.Context, id string) error {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := s.ClustersApi.DeleteCluster(ctx, id).Execute()
+	return apiError(resp, err)
+}
+
+// ListClients returns the clients of the supplied cluster.
+func (s *Service) ListClients(ctx context.Context, clusterID string) ([]Client, error) {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l, resp, err := s.ClustersApi.GetClients(ctx, clusterID).Execute()
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	clients := make([]Client, len(l))
+	for i := range l {
+		clients[i] = Client{ID: l[i].ClientId, Name: l[i].Name}
+	}
+	return clients, nil
+}
+
+// GetClient returns the connection details of a client of the supplied
+// cluster.
+func (s *Service) GetClient(ctx context.Context, clusterID, clientID string) (*ClientDetails, error) {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, resp, err := s.ClustersApi.GetClient(ctx, clusterID, clientID).Execute()
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	return &ClientDetails{
+		Client:                      Client{ID: d.ZEEBE_CLIENT_ID, Name: d.Name},
+		ZeebeAddress:                d.ZEEBE_ADDRESS,
+		ZeebeAuthorizationServerURL: d.ZEEBE_AUTHORIZATION_SERVER_URL,
+	}, nil
+}
+
+// CreateClient creates a client of the supplied cluster.
+func (s *Service) CreateClient(ctx context.Context, clusterID, name string) (*ClientCredentials, error) {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, resp, err := s.ClustersApi.CreateClient(ctx, clusterID).
+		CreateClusterClientBody(console.CreateClusterClientBody{ClientName: name}).
+		Execute()
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	return &ClientCredentials{ID: c.ClientId, Secret: c.ClientSecret}, nil
+}
+
+// DeleteClient deletes a client of the supplied cluster.
+func (s *Service) DeleteClient(ctx context.Context, clusterID, clientID string) error {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := s.ClustersApi.DeleteClient(ctx, clusterID, clientID).Execute()
+	return apiError(resp, err)
+}
+
+// GetParameters returns the values the parameters of new clusters may take.
+func (s *Service) GetParameters(ctx context.Context) (*Parameters, error) {
+	ctx, err := s.withToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, resp, err := s.ClustersApi.GetParameters(ctx).Execute()
+	if err != nil {
+		return nil, apiError(resp, err)
+	}
+	params := func(l []console.ParametersChannelsInnerAllowedGenerationsInner) []Parameter {
+		out := make([]Parameter, len(l))
+		for i := range l {
+			out[i] = Parameter{ID: l[i].Uuid, Name: l[i].Name}
+		}
+		return out
+	}
+	out := &Parameters{
+		Channels:  make([]Channel, len(p.Channels)),
+		Regions:   params(p.Regions),
+		PlanTypes: params(p.ClusterPlanTypes),
+	}
+	for i, c := range p.Channels {
+		out.Channels[i] = Channel{
+			Parameter:         Parameter{ID: c.Uuid, Name: c.Name},
+			Generations:       params(c.AllowedGenerations),
+			DefaultGeneration: Parameter{ID: c.DefaultGeneration.Uuid, Name: c.DefaultGeneration.Name},
+		}
+	}
+	return out, nil
+}
+
+// cluster returns the supplied cluster of the Console API as a Cluster.
+func cluster(c *console.Cluster) Cluster {
+	status := func(h *console.ClusterHealthStatus) HealthStatus {
+		if h == nil {
+			return ""
+		}
+		return HealthStatus(*h)
+	}
+	return Cluster{
+		ID: c.Uuid,
+		ClusterSpec: ClusterSpec{
+			Name:       c.Name,
+			Channel:    c.Channel.Uuid,
+			Generation: c.Generation.Uuid,
+			Region:     c.Region.Uuid,
+			PlanType:   c.PlanType.Uuid,
+		},
+		Status: ClusterStatus{
+			Ready:    HealthStatus(c.Status.Ready),
+			Zeebe:    status(c.Status.ZeebeStatus),
+			Operate:  status(c.Status.OperateStatus),
+			Tasklist: status(c.Status.TasklistStatus),
+			Optimize: status(c.Status.OptimizeStatus),
+		},
+		Endpoints: ClusterEndpoints{
+			Zeebe:    c.Links.GetZeebe(),
+			Operate:  c.Links.GetOperate(),
+			Tasklist: c.Links.GetTasklist(),
+			Optimize: c.Links.GetOptimize(),
+		},
+	}
+}
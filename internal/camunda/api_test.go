@@ -0,0 +1,62 @@
+package camunda
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestServiceErrors(t *testing.T) {
+	type want struct {
+		notFound     bool
+		unauthorized bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		status int
+		want   want
+	}{
+		"NotFound": {
+			reason: "A 404 response should be returned as a NotFoundError.",
+			status: http.StatusNotFound,
+			want:   want{notFound: true},
+		},
+		"Unauthorized": {
+			reason: "A 401 response should be returned as an UnauthorizedError.",
+			status: http.StatusUnauthorized,
+			want:   want{unauthorized: true},
+		},
+		"Forbidden": {
+			reason: "A 403 response should be returned as an UnauthorizedError.",
+			status: http.StatusForbidden,
+			want:   want{unauthorized: true},
+		},
+		"ServerError": {
+			reason: "Other error responses should not be typed.",
+			status: http.StatusInternalServerError,
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			_, err := newTestService(srv).GetCluster(context.Background(), "cluster")
+			if err == nil {
+				t.Fatalf("\n%s\ns.GetCluster(...): expected an error", tc.reason)
+			}
+			got := want{notFound: IsNotFound(err), unauthorized: IsUnauthorized(err)}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ns.GetCluster(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package camunda
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-camunda/internal/metrics"
+)
+
+const (
+	errListClusters = "cannot list clusters"
+	errListClients  = "cannot list clients"
+	errGetCluster   = "cannot get cluster"
+	errGetClient    = "cannot get client"
+)
+
+// Kinds of cached observations, used as metric labels.
+const (
+	cacheKindCluster = "cluster"
+	cacheKindClient  = "client"
+)
+
+var caches = struct {
+	sync.Mutex
+	m map[string]*keyedCache
+}{m: map[string]*keyedCache{}}
+
+// A keyedCache is the observation cache of a ProviderConfig and a hash of the
+// credentials its observations were made with.
+type keyedCache struct {
+	hash  string
+	cache *Cache
+}
+
+// ObservationCache returns the observation cache of the ProviderConfig
+// identified by the supplied key, creating it with the supplied TTL if it does
+// not exist yet. A new cache is created when the ProviderConfig's credentials
+// change, since they may belong to another organization.
+func ObservationCache(key string, creds []byte, ttl time.Duration) *Cache {
+	hash := credentialsHash(creds)
+	caches.Lock()
+	defer caches.Unlock()
+	if kc, ok := caches.m[key]; ok && kc.hash == hash {
+		return kc.cache
+	}
+	c := NewCache(ttl)
+	caches.m[key] = &keyedCache{hash: hash, cache: c}
+	return c
+}
+
+// A Cache serves observations of clusters and clients from lists that are
+// fetched from the Console API at most once per TTL, so that the number of
+// Console API calls does not grow with the number of managed resources.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	clusters *listEntry[Cluster]
+
+	// clients holds the clients of each cluster, and details the connection
+	// details of each client, keyed by detailsKey. Both only hold entries of
+	// clusters that were observed since they were last invalidated.
+	mu      sync.Mutex
+	clients map[string]*listEntry[Client]
+	details map[string]*ClientDetails
+}
+
+func detailsKey(clusterID, clientID string) string {
+	return clusterID + "/" + clientID
+}
+
+// NewCache returns an empty Cache whose lists expire after the supplied TTL.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:      ttl,
+		now:      time.Now,
+		clusters: &listEntry[Cluster]{},
+		clients:  map[string]*listEntry[Client]{},
+		details:  map[string]*ClientDetails{},
+	}
+}
+
+// A listEntry is a cached list of one kind of Console API resource. Its mutex
+// is held while the list is fetched, so that concurrent callers wait for a
+// single fetch instead of issuing their own.
+type listEntry[T any] struct {
+	mu      sync.Mutex
+	items   []T
+	fetched time.Time
+}
+
+func (e *listEntry[T]) get(c *Cache, kind string, fetch func() ([]T, error)) ([]T, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.fetched.IsZero() && c.now().Sub(e.fetched) < c.ttl {
+		metrics.ObservationCacheHits.WithLabelValues(kind).Inc()
+		return e.items, nil
+	}
+	metrics.ObservationCacheMisses.WithLabelValues(kind).Inc()
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	e.items, e.fetched = items, c.now()
+	return items, nil
+}
+
+func (e *listEntry[T]) invalidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fetched = time.Time{}
+}
+
+// GetCluster returns the cluster with the supplied ID. It returns false if the
+// cluster does not exist.
+func (c *Cache) GetCluster(ctx context.Context, api ClusterAPI, id string) (*Cluster, bool, error) {
+	clusters, err := c.clusters.get(c, cacheKindCluster, func() ([]Cluster, error) {
+		l, err := api.ListClusters(ctx)
+		return l, errors.Wrap(err, errListClusters)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range clusters {
+		if clusters[i].ID == id {
+			return &clusters[i], true, nil
+		}
+	}
+
+	// The cached list may predate the cluster, so confirm that it does not
+	// exist before reporting it as such.
+	cl, err := api.GetCluster(ctx, id)
+	if IsNotFound(err) {
+		c.dropClients(id)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, errGetCluster)
+	}
+	c.clusters.invalidate()
+	return cl, true, nil
+}
+
+// GetClient returns the connection details of the client with the supplied ID
+// of the supplied cluster. It returns false if the client does not exist.
+func (c *Cache) GetClient(ctx context.Context, api ClientAPI, clusterID, clientID string) (*ClientDetails, bool, error) {
+	clients, err := c.clientsOf(clusterID).get(c, cacheKindClient, func() ([]Client, error) {
+		l, err := api.ListClients(ctx, clusterID)
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return l, errors.Wrap(err, errListClients)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	listed := false
+	for i := range clients {
+		if clients[i].ID == clientID {
+			listed = true
+			break
+		}
+	}
+
+	key := detailsKey(clusterID, clientID)
+	c.mu.Lock()
+	d, ok := c.details[key]
+	c.mu.Unlock()
+	if listed && ok {
+		return d, true, nil
+	}
+
+	// The connection details of a client are not part of the list and the
+	// cached list may predate the client, so get the client itself. Its
+	// connection details never change, so they are kept until the clients of
+	// its cluster are invalidated.
+	d, err = api.GetClient(ctx, clusterID, clientID)
+	if IsNotFound(err) {
+		c.mu.Lock()
+		delete(c.details, key)
+		c.mu.Unlock()
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, errGetClient)
+	}
+	if !listed {
+		c.InvalidateClients(clusterID)
+	}
+	c.mu.Lock()
+	c.details[key] = d
+	c.mu.Unlock()
+	return d, true, nil
+}
+
+// InvalidateClusters drops the cached clusters, for example because a cluster
+// was created or deleted. The cached clients of all clusters are dropped too,
+// so that none are kept for clusters that no longer exist.
+func (c *Cache) InvalidateClusters() {
+	c.clusters.invalidate()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clients = map[string]*listEntry[Client]{}
+	c.details = map[string]*ClientDetails{}
+}
+
+// InvalidateClients drops the cached clients of the supplied cluster and their
+// connection details, for example because a client was created or deleted.
+func (c *Cache) InvalidateClients(clusterID string) {
+	c.dropClients(clusterID)
+}
+
+func (c *Cache) dropClients(clusterID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, clusterID)
+	prefix := detailsKey(clusterID, "")
+	for k := range c.details {
+		if strings.HasPrefix(k, prefix) {
+			delete(c.details, k)
+		}
+	}
+}
+
+func (c *Cache) clientsOf(clusterID string) *listEntry[Client] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.clients[clusterID]
+	if !ok {
+		e = &listEntry[Client]{}
+		c.clients[clusterID] = e
+	}
+	return e
+}
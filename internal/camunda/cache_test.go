@@ -0,0 +1,273 @@
+package camunda
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	console "github.com/sijoma/console-customer-api-go"
+	"golang.org/x/oauth2"
+)
+
+// newTestService returns a Service that talks to the supplied server.
+func newTestService(srv *httptest.Server) *Service {
+	cfg := console.NewConfiguration()
+	cfg.Servers = console.ServerConfigurations{{URL: srv.URL}}
+	return &Service{APIClient: *console.NewAPIClient(cfg), tokens: oauth2.StaticTokenSource(&oauth2.Token{})}
+}
+
+func TestCacheGetCluster(t *testing.T) {
+	var lists, gets int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/clusters":
+			atomic.AddInt32(&lists, 1)
+			_ = json.NewEncoder(w).Encode([]console.Cluster{{Uuid: "listed", Status: console.ClusterStatus{Ready: console.HEALTHY}}})
+		case "/clusters/new":
+			atomic.AddInt32(&gets, 1)
+			_ = json.NewEncoder(w).Encode(console.Cluster{Uuid: "new", Status: console.ClusterStatus{Ready: console.HEALTHY}})
+		default:
+			atomic.AddInt32(&gets, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	c := NewCache(time.Minute)
+	c.now = func() time.Time { return now }
+	svc := newTestService(srv)
+
+	type want struct {
+		found bool
+		lists int32
+		gets  int32
+	}
+
+	// The cases share the cache and run in order.
+	cases := []struct {
+		name   string
+		reason string
+		id     string
+		before func()
+		want   want
+	}{
+		{
+			name:   "FirstObservation",
+			reason: "The first observation should list the clusters.",
+			id:     "listed",
+			want:   want{found: true, lists: 1},
+		},
+		{
+			name:   "CachedObservation",
+			reason: "Observations within the TTL should be served from the cache.",
+			id:     "listed",
+			want:   want{found: true, lists: 1},
+		},
+		{
+			name:   "NotListed",
+			reason: "A cluster that is not listed should be confirmed with a direct call.",
+			id:     "new",
+			want:   want{found: true, lists: 1, gets: 1},
+		},
+		{
+			name:   "NotFound",
+			reason: "A cluster that neither is listed nor exists should be reported as not found.",
+			id:     "gone",
+			want:   want{found: false, lists: 2, gets: 2},
+		},
+		{
+			name:   "Expired",
+			reason: "The clusters should be listed again once the TTL expired.",
+			id:     "listed",
+			before: func() { now = now.Add(time.Minute) },
+			want:   want{found: true, lists: 3, gets: 2},
+		},
+		{
+			name:   "Invalidated",
+			reason: "The clusters should be listed again once they were invalidated.",
+			id:     "listed",
+			before: c.InvalidateClusters,
+			want:   want{found: true, lists: 4, gets: 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.before != nil {
+				tc.before()
+			}
+			_, found, err := c.GetCluster(context.Background(), svc, tc.id)
+			if err != nil {
+				t.Fatalf("\n%s\nc.GetCluster(...): unexpected error: %v", tc.reason, err)
+			}
+			got := want{found: found, lists: atomic.LoadInt32(&lists), gets: atomic.LoadInt32(&gets)}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nc.GetCluster(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCacheGetClient(t *testing.T) {
+	var lists, gets int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/clusters/cluster/clients":
+			atomic.AddInt32(&lists, 1)
+			_ = json.NewEncoder(w).Encode([]console.ClusterClient{{ClientId: "client"}})
+		case r.URL.Path == "/clusters/cluster/clients/client":
+			atomic.AddInt32(&gets, 1)
+			_ = json.NewEncoder(w).Encode(console.ClusterClientConnectionDetails{ZEEBE_CLIENT_ID: "client"})
+		case strings.HasPrefix(r.URL.Path, "/clusters/cluster/clients/"):
+			atomic.AddInt32(&gets, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewCache(time.Minute)
+	svc := newTestService(srv)
+
+	type want struct {
+		found bool
+		lists int32
+		gets  int32
+	}
+
+	// The cases share the cache and run in order.
+	cases := []struct {
+		name   string
+		reason string
+		id     string
+		before func()
+		want   want
+	}{
+		{
+			name:   "FirstObservation",
+			reason: "The first observation should list the clients and get the connection details.",
+			id:     "client",
+			want:   want{found: true, lists: 1, gets: 1},
+		},
+		{
+			name:   "CachedObservation",
+			reason: "Observations within the TTL should be served from the cache.",
+			id:     "client",
+			want:   want{found: true, lists: 1, gets: 1},
+		},
+		{
+			name:   "NotFound",
+			reason: "A client that neither is listed nor exists should be reported as not found.",
+			id:     "gone",
+			want:   want{found: false, lists: 1, gets: 2},
+		},
+		{
+			name:   "Invalidated",
+			reason: "Connection details should be dropped along with the clients when they are invalidated.",
+			id:     "client",
+			before: func() { c.InvalidateClients("cluster") },
+			want:   want{found: true, lists: 2, gets: 3},
+		},
+		{
+			name:   "OtherClusterInvalidated",
+			reason: "Connection details should be kept when the clients of another cluster are invalidated.",
+			id:     "client",
+			before: func() { c.InvalidateClients("other") },
+			want:   want{found: true, lists: 2, gets: 3},
+		},
+		{
+			name:   "ClustersInvalidated",
+			reason: "Clients and their connection details should be dropped when the clusters are invalidated.",
+			id:     "client",
+			before: c.InvalidateClusters,
+			want:   want{found: true, lists: 3, gets: 4},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.before != nil {
+				tc.before()
+			}
+			_, found, err := c.GetClient(context.Background(), svc, "cluster", tc.id)
+			if err != nil {
+				t.Fatalf("\n%s\nc.GetClient(...): unexpected error: %v", tc.reason, err)
+			}
+			got := want{found: found, lists: atomic.LoadInt32(&lists), gets: atomic.LoadInt32(&gets)}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nc.GetClient(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCacheDropClients(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/clusters":
+			_ = json.NewEncoder(w).Encode([]console.Cluster{})
+		case "/clusters/a/clients", "/clusters/b/clients":
+			_ = json.NewEncoder(w).Encode([]console.ClusterClient{{ClientId: "client"}})
+		case "/clusters/a/clients/client", "/clusters/b/clients/client":
+			_ = json.NewEncoder(w).Encode(console.ClusterClientConnectionDetails{ZEEBE_CLIENT_ID: "client"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	svc := newTestService(srv)
+
+	type want struct {
+		clients int
+		details int
+	}
+
+	cases := map[string]struct {
+		reason string
+		drop   func(c *Cache)
+		want   want
+	}{
+		"ClientsInvalidated": {
+			reason: "Invalidating the clients of a cluster should drop only its entries.",
+			drop:   func(c *Cache) { c.InvalidateClients("a") },
+			want:   want{clients: 1, details: 1},
+		},
+		"ClustersInvalidated": {
+			reason: "Invalidating the clusters should drop the entries of all clusters.",
+			drop:   func(c *Cache) { c.InvalidateClusters() },
+			want:   want{clients: 0, details: 0},
+		},
+		"ClusterNotFound": {
+			reason: "Observing that a cluster does not exist should drop its entries.",
+			drop: func(c *Cache) {
+				_, _, _ = c.GetCluster(context.Background(), svc, "a")
+			},
+			want: want{clients: 1, details: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCache(time.Minute)
+			for _, cluster := range []string{"a", "b"} {
+				if _, _, err := c.GetClient(context.Background(), svc, cluster, "client"); err != nil {
+					t.Fatalf("\n%s\nc.GetClient(...): unexpected error: %v", tc.reason, err)
+				}
+			}
+			tc.drop(c)
+			got := want{clients: len(c.clients), details: len(c.details)}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nCached entries: -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
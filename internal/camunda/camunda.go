@@ -2,66 +2,335 @@ package camunda
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	console "github.com/sijoma/console-customer-api-go"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/crossplane/provider-camunda/internal/metrics"
 )
 
 const userAgent = "go/crossplane"
 
+const (
+	defaultTokenURL = "https://login.cloud.camunda.io/oauth/token"
+	defaultAudience = "api.cloud.camunda.io"
+
+	// claimOrganizationID is the claim of a Console API access token that
+	// holds the ID of the organization the token belongs to.
+	claimOrganizationID = "https://camunda.com/orgId"
+
+	// tokenTimeout bounds the exchange of credentials for an access token.
+	tokenTimeout = 30 * time.Second
+)
+
 // Service connects to Camunda Cloud
 type Service struct {
 	console.APIClient
-	AccessToken string
+
+	tokens oauth2.TokenSource
 }
 
-var camundaService *Service
+// Token returns a valid access token of the Service, exchanging its
+// credentials for a new one if the current one expired.
+func (s *Service) Token() (*oauth2.Token, error) {
+	return s.tokens.Token()
+}
 
-// NewService creates a Camunda service to connect to Camunda Cloud
-func NewService(ctx context.Context, creds []byte) (*Service, error) {
-	log, _ := logr.FromContext(ctx)
-	// Singleton to not refetch access token all the time
-	// ToDo: This probably causes issues when the token is expired
-	if camundaService != nil {
-		return camundaService, nil
-	}
+// services are the Services of ProviderConfigs, by ProviderConfig key.
+var services = struct {
+	sync.Mutex
+	m map[string]*keyedService
+}{m: map[string]*keyedService{}}
+
+// A keyedService is the Service of a ProviderConfig and a hash of the
+// credentials it was created from.
+type keyedService struct {
+	hash    string
+	service *Service
+}
+
+// Credentials of a Console API client.
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Audience     string
+
+	// APIURL is the URL of the Console API. It is derived from the audience
+	// unless the credentials specify it.
+	APIURL string
+}
+
+// ParseCredentials parses the JSON encoded credentials of a ProviderConfig.
+func ParseCredentials(creds []byte) (*Credentials, error) {
 	camundaCreds := map[string]string{}
 	if err := json.Unmarshal(creds, &camundaCreds); err != nil {
 		return nil, err
 	}
 	tokenUrl, ok := camundaCreds["token_url"]
 	if !ok {
-		tokenUrl = "https://login.cloud.camunda.io/oauth/token"
+		tokenUrl = defaultTokenURL
 	}
 
 	audience, ok := camundaCreds["audience"]
 	if !ok {
-		audience = "api.cloud.camunda.io"
+		audience = defaultAudience
 	}
-	config := clientcredentials.Config{
+
+	apiURL, ok := camundaCreds["api_url"]
+	if !ok {
+		apiURL = "https://" + audience
+	}
+	return &Credentials{
 		ClientID:     camundaCreds["client_id"],
 		ClientSecret: camundaCreds["client_secret"],
 		TokenURL:     tokenUrl,
+		Audience:     audience,
+		APIURL:       apiURL,
+	}, nil
+}
+
+// Token exchanges the credentials for an access token. Like Console API calls,
+// the exchange is recorded in the metrics and traces, and it times out after
+// tokenTimeout.
+func (c *Credentials) Token(ctx context.Context) (*oauth2.Token, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: newTransport(http.DefaultTransport), Timeout: tokenTimeout})
+	config := clientcredentials.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		TokenURL:     c.TokenURL,
 		EndpointParams: url.Values{
-			"audience": []string{audience},
+			"audience": []string{c.Audience},
 		},
 	}
-	token, err := config.Token(ctx)
+	t, err := config.Token(ctx)
 	if err != nil {
-		log.Error(err, "unable to fetch token for camunda provider")
+		metrics.TokenRefreshes.WithLabelValues(metrics.ResultError).Inc()
 		return nil, err
 	}
+	metrics.TokenRefreshes.WithLabelValues(metrics.ResultSuccess).Inc()
+	return t, nil
+}
+
+// NewService returns the Service of the ProviderConfig identified by the
+// supplied key, for example the key of a resolved ProviderConfig. The Service
+// is created with Authenticate on first use and again whenever the
+// ProviderConfig's credentials change, so that every ProviderConfig works in
+// the organization of its own credentials. Services refresh their access
+// token as it expires.
+func NewService(ctx context.Context, key string, creds []byte) (API, error) {
+	hash := credentialsHash(creds)
+	services.Lock()
+	ks, ok := services.m[key]
+	services.Unlock()
+	if ok && ks.hash == hash {
+		return ks.service, nil
+	}
+
+	// Authenticate without holding the lock, so that a slow authorization
+	// server does not hold up other ProviderConfigs.
+	svc, err := Authenticate(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+	services.Lock()
+	defer services.Unlock()
+	services.m[key] = &keyedService{hash: hash, service: svc}
+	return svc, nil
+}
+
+// Forget drops the Service and observation cache of the ProviderConfig
+// identified by the supplied key, for example because it was deleted.
+func Forget(key string) {
+	services.Lock()
+	delete(services.m, key)
+	services.Unlock()
+
+	caches.Lock()
+	delete(caches.m, key)
+	caches.Unlock()
+}
 
-	log.Info("Authenticated against Camunda API", "audience", audience, "tokenUrl", tokenUrl)
+// credentialsHash returns a hash of the supplied credentials, so that
+// credentials are not kept in memory longer than needed to tell them apart.
+func credentialsHash(creds []byte) string {
+	h := sha256.Sum256(creds)
+	return hex.EncodeToString(h[:])
+}
+
+// Authenticate exchanges the supplied credentials for an access token and
+// returns a new Service that uses it. The Service exchanges the credentials
+// again whenever its access token expires.
+func Authenticate(ctx context.Context, creds []byte) (*Service, error) {
+	log := logr.FromContextOrDiscard(ctx)
+	c, err := ParseCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.Token(ctx)
+	if err != nil {
+		log.Error(err, "unable to fetch token for camunda provider")
+		return nil, tokenError(err)
+	}
+
+	log.Info("Authenticated against Camunda API", "audience", c.Audience, "tokenUrl", c.TokenURL)
+
+	return newService(c.APIURL, oauth2.ReuseTokenSource(token, &refresher{credentials: c}), http.DefaultTransport), nil
+}
 
+// A refresher is an oauth2.TokenSource that exchanges credentials for a new
+// access token.
+type refresher struct {
+	credentials *Credentials
+}
+
+// Token exchanges the credentials for a new access token. The exchange is not
+// bound to the context of the Console API call that needs the token, since
+// the token outlives it. It is bounded by a timeout instead, since callers
+// that need a token wait for it while it is being exchanged.
+func (r *refresher) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenTimeout)
+	defer cancel()
+	t, err := r.credentials.Token(ctx)
+	if err != nil {
+		return nil, tokenError(errors.Wrap(err, "cannot exchange credentials for an access token"))
+	}
+	return t, nil
+}
+
+// newService returns a Service that calls the Console API at the supplied URL
+// with the access tokens of the supplied TokenSource, using the supplied
+// RoundTripper.
+func newService(apiURL string, tokens oauth2.TokenSource, rt http.RoundTripper) *Service {
+	return &Service{APIClient: *newAPIClient(apiURL, rt), tokens: tokens}
+}
+
+func newAPIClient(apiURL string, rt http.RoundTripper) *console.APIClient {
 	cfg := console.NewConfiguration()
-	cfg.Scheme = "https"
-	cfg.Host = audience
+	cfg.Servers = console.ServerConfigurations{{URL: apiURL}}
 	cfg.UserAgent = userAgent
-	client := console.NewAPIClient(cfg)
-	camundaService = &Service{APIClient: *client, AccessToken: token.AccessToken}
-	return camundaService, err
+	cfg.HTTPClient = &http.Client{Transport: newTransport(rt)}
+	return console.NewAPIClient(cfg)
+}
+
+// newTransport instruments the supplied RoundTripper with metrics and a span
+// per Console API call. Spans are children of the span in the request context,
+// if any.
+func newTransport(next http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(metrics.NewTransport(next),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "Console " + metrics.Endpoint(r)
+		}))
+}
+
+// An UnauthorizedError indicates that credentials were rejected by the
+// authorization server or the Console API.
+type UnauthorizedError struct {
+	Err error
+}
+
+func (e UnauthorizedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e UnauthorizedError) Unwrap() error {
+	return e.Err
+}
+
+// IsUnauthorized returns true if the supplied error is an UnauthorizedError.
+func IsUnauthorized(err error) bool {
+	u := UnauthorizedError{}
+	return errors.As(err, &u)
+}
+
+// tokenError returns the supplied error of a token exchange as an
+// UnauthorizedError if the authorization server rejected the credentials.
+// Other errors, such as rate limits and timeouts, say nothing about the
+// credentials.
+func tokenError(err error) error {
+	re := &oauth2.RetrieveError{}
+	if errors.As(err, &re) && re.Response != nil {
+		switch re.Response.StatusCode {
+		case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+			return UnauthorizedError{err}
+		}
+	}
+	return err
+}
+
+// A Health is the result of a successful health check of credentials.
+type Health struct {
+	// OrganizationID is the ID of the organization the credentials belong to.
+	OrganizationID string
+
+	// Audience is the audience the access token was issued for.
+	Audience string
+
+	// Token is the access token the credentials were exchanged for.
+	Token *oauth2.Token
+}
+
+// CheckHealth exchanges the supplied credentials for an access token and uses
+// it for a cheap Console API call to verify that the Console API is reachable
+// and accepts the token.
+func CheckHealth(ctx context.Context, creds []byte) (*Health, error) {
+	c, err := ParseCredentials(creds)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse credentials")
+	}
+
+	token, err := c.Token(ctx)
+	if err != nil {
+		return nil, tokenError(errors.Wrap(err, "cannot exchange credentials for an access token"))
+	}
+
+	// The call is made like any other Console API call, so that it is
+	// recorded in the metrics and traces.
+	_, err = newService(c.APIURL, oauth2.StaticTokenSource(token), http.DefaultTransport).GetParameters(ctx)
+	if IsUnauthorized(err) {
+		return nil, UnauthorizedError{errors.Wrap(err, "Console API rejected the access token")}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot reach Console API")
+	}
+
+	return &Health{
+		OrganizationID: OrganizationID(token.AccessToken),
+		Audience:       c.Audience,
+		Token:          token,
+	}, nil
+}
+
+// OrganizationID returns the ID of the organization the supplied access token
+// belongs to. It returns an empty string if the token does not carry it. The
+// token's signature is not verified.
+func OrganizationID(accessToken string) string {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	claims := map[string]any{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	id, _ := claims[claimOrganizationID].(string)
+	return id
 }
@@ -0,0 +1,195 @@
+package camunda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/crossplane/provider-camunda/internal/metrics"
+)
+
+func TestOrganizationID(t *testing.T) {
+	token := func(payload string) string {
+		return "header." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+	}
+
+	cases := map[string]struct {
+		reason string
+		token  string
+		want   string
+	}{
+		"NotAJWT": {
+			reason: "An empty ID should be returned for tokens that are not JWTs.",
+			token:  "opaque",
+			want:   "",
+		},
+		"MalformedPayload": {
+			reason: "An empty ID should be returned for tokens with a malformed payload.",
+			token:  "header.!!!.signature",
+			want:   "",
+		},
+		"NoClaim": {
+			reason: "An empty ID should be returned for tokens without an organization claim.",
+			token:  token(`{"sub":"client"}`),
+			want:   "",
+		},
+		"Claim": {
+			reason: "The organization claim of the token should be returned.",
+			token:  token(`{"sub":"client","https://camunda.com/orgId":"org"}`),
+			want:   "org",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := OrganizationID(tc.token)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nOrganizationID(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		tokenStatus  int
+		apiStatus    int
+		unauthorized bool
+		healthy      bool
+
+		// errors is whether failed requests should be recorded in the
+		// metrics. Failed token exchanges are retried with other ways of
+		// passing the credentials, so their number is not checked.
+		errors bool
+	}{
+		"Healthy": {
+			reason:      "Credentials that are exchanged for a token the Console API accepts should be healthy.",
+			tokenStatus: http.StatusOK,
+			apiStatus:   http.StatusOK,
+			healthy:     true,
+		},
+		"BadRequest": {
+			reason:       "Credentials the authorization server rejects as invalid should be unauthorized.",
+			tokenStatus:  http.StatusBadRequest,
+			unauthorized: true,
+			errors:       true,
+		},
+		"Unauthorized": {
+			reason:       "Credentials the authorization server rejects should be unauthorized.",
+			tokenStatus:  http.StatusUnauthorized,
+			unauthorized: true,
+			errors:       true,
+		},
+		"Forbidden": {
+			reason:       "Credentials the authorization server forbids should be unauthorized.",
+			tokenStatus:  http.StatusForbidden,
+			unauthorized: true,
+			errors:       true,
+		},
+		"TooManyRequests": {
+			reason:      "A rate limited token exchange should not mark the credentials as unauthorized.",
+			tokenStatus: http.StatusTooManyRequests,
+			errors:      true,
+		},
+		"RequestTimeout": {
+			reason:      "A timed out token exchange should not mark the credentials as unauthorized.",
+			tokenStatus: http.StatusRequestTimeout,
+			errors:      true,
+		},
+		"ServerError": {
+			reason:      "A failing authorization server should not mark the credentials as unauthorized.",
+			tokenStatus: http.StatusInternalServerError,
+			errors:      true,
+		},
+		"RejectedToken": {
+			reason:       "A token the Console API rejects should be unauthorized.",
+			tokenStatus:  http.StatusOK,
+			apiStatus:    http.StatusForbidden,
+			unauthorized: true,
+			errors:       true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/oauth/token" {
+					w.WriteHeader(tc.tokenStatus)
+					_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "t0k3n", "token_type": "Bearer", "expires_in": 3600})
+					return
+				}
+				w.WriteHeader(tc.apiStatus)
+				_, _ = w.Write([]byte("{}"))
+			}))
+			defer srv.Close()
+
+			recorded := func() float64 {
+				return testutil.ToFloat64(metrics.ConsoleErrors.WithLabelValues("POST /oauth/token", metrics.StatusClass(tc.tokenStatus))) +
+					testutil.ToFloat64(metrics.ConsoleErrors.WithLabelValues("GET /clusters/parameters", metrics.StatusClass(tc.apiStatus)))
+			}
+			before := recorded()
+
+			creds, _ := json.Marshal(map[string]string{"client_id": "id", "client_secret": "secret", "token_url": srv.URL + "/oauth/token", "api_url": srv.URL})
+			_, err := CheckHealth(context.Background(), creds)
+			if diff := cmp.Diff(tc.healthy, err == nil); diff != "" {
+				t.Errorf("\n%s\nCheckHealth(...): -want healthy, +got healthy:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.unauthorized, IsUnauthorized(err)); diff != "" {
+				t.Errorf("\n%s\nIsUnauthorized(...): -want, +got:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.errors, recorded() > before); diff != "" {
+				t.Errorf("\n%s\nCheckHealth(...): -want errors recorded, +got errors recorded:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestForget(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		key    string
+		kept   bool
+	}{
+		"Forgotten": {
+			reason: "The Service and observation cache of the forgotten ProviderConfig should be dropped.",
+			key:    "team/default",
+		},
+		"OtherKept": {
+			reason: "The Service and observation cache of other ProviderConfigs should be kept.",
+			key:    "other/default",
+			kept:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			creds := []byte("creds")
+			services.Lock()
+			services.m["team/default"] = &keyedService{hash: credentialsHash(creds), service: &Service{}}
+			services.Unlock()
+			c := ObservationCache("team/default", creds, time.Minute)
+
+			Forget(tc.key)
+
+			services.Lock()
+			_, ok := services.m["team/default"]
+			services.Unlock()
+			if diff := cmp.Diff(tc.kept, ok); diff != "" {
+				t.Errorf("\n%s\nForget(...): -want Service kept, +got Service kept:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.kept, ObservationCache("team/default", creds, time.Minute) == c); diff != "" {
+				t.Errorf("\n%s\nForget(...): -want cache kept, +got cache kept:\n%s\n", tc.reason, diff)
+			}
+			Forget("team/default")
+		})
+	}
+}
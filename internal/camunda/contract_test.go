@@ -0,0 +1,163 @@
+package camunda
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2"
+)
+
+var (
+	record = flag.Bool("record", false, "Record the live contract against the Console API the CAMUNDA_CREDENTIALS environment variable grants access to.")
+	update = flag.Bool("update", false, "Update the golden files of the contract tests from their recordings.")
+)
+
+const (
+	contractDir = "testdata/contract"
+
+	// liveContract is the name of the contract recorded against the Console
+	// API with -record.
+	liveContract = "live"
+
+	suffixInteractions = ".interactions.json"
+	suffixGolden       = ".golden.json"
+)
+
+// A contract is what the Service returned for the calls of a contract
+// scenario.
+type contract struct {
+	Parameters *Parameters
+	Clusters   []Cluster
+	Clients    map[string][]Client
+	Details    map[string]*ClientDetails
+}
+
+// runContract makes the read only calls the provider relies on. Every call
+// depends only on the responses to earlier calls, so that the scenario can be
+// replayed from a recording.
+func runContract(ctx context.Context, s *Service) (*contract, error) {
+	c := &contract{Clients: map[string][]Client{}, Details: map[string]*ClientDetails{}}
+	p, err := s.GetParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.Parameters = p
+
+	l, err := s.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, listed := range l {
+		cl, err := s.GetCluster(ctx, listed.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.Clusters = append(c.Clusters, *cl)
+
+		clients, err := s.ListClients(ctx, cl.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.Clients[cl.ID] = clients
+		if len(clients) == 0 {
+			continue
+		}
+		d, err := s.GetClient(ctx, cl.ID, clients[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		c.Details[cl.ID] = d
+	}
+	return c, nil
+}
+
+// TestContract replays the recorded Console API interactions in testdata and
+// compares what the Service makes of them with the golden files next to them.
+// Run it with -record to refresh the live recording against the Console API,
+// or with -update to refresh the golden files after an intended change.
+func TestContract(t *testing.T) {
+	if *record {
+		recordContract(t)
+	}
+
+	recordings, err := filepath.Glob(filepath.Join(contractDir, "*"+suffixInteractions))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range recordings {
+		file := file
+		name := strings.TrimSuffix(filepath.Base(file), suffixInteractions)
+		t.Run(name, func(t *testing.T) {
+			rp, err := LoadReplayer(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := runContract(context.Background(), newService("https://console.invalid", oauth2.StaticTokenSource(&oauth2.Token{}), rp))
+			if err != nil {
+				t.Fatalf("runContract(...): %v", err)
+			}
+			if diff := cmp.Diff([]RecordedRequest(nil), rp.Unreplayed()); diff != "" {
+				t.Errorf("\nAll recorded interactions should be replayed.\nrunContract(...): -want, +got:\n%s\n", diff)
+			}
+
+			golden := filepath.Join(contractDir, name+suffixGolden)
+			if *update || *record && name == liveContract {
+				writeGolden(t, golden, got)
+			}
+			b, err := os.ReadFile(filepath.Clean(golden))
+			if err != nil {
+				t.Fatalf("Cannot read golden file, run with -update to write it: %v", err)
+			}
+			want := &contract{}
+			if err := json.Unmarshal(b, want); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\nThe Service should map the recorded responses as before.\nrunContract(...): -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
+
+// recordContract runs the contract scenario against the Console API and saves
+// the sanitized interactions as the live recording.
+func recordContract(t *testing.T) {
+	t.Helper()
+	creds := os.Getenv("CAMUNDA_CREDENTIALS")
+	if creds == "" {
+		t.Fatal("-record requires the CAMUNDA_CREDENTIALS environment variable to hold ProviderConfig credentials")
+	}
+	c, err := ParseCredentials([]byte(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := c.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRecorder(http.DefaultTransport)
+	if _, err := runContract(context.Background(), newService(c.APIURL, oauth2.StaticTokenSource(token), r)); err != nil {
+		t.Fatalf("runContract(...): %v", err)
+	}
+	if err := r.Save(filepath.Join(contractDir, liveContract+suffixInteractions)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeGolden(t *testing.T, file string, c *contract) {
+	t.Helper()
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, append(b, '\n'), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,70 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/crossplane/provider-camunda/internal/camunda"
+)
+
+var _ camunda.API = &MockAPI{}
+
+// A MockAPI is a camunda.API whose methods call the corresponding mock
+// functions. It is meant for unit tests that do not need a Server.
+type MockAPI struct {
+	MockListClusters  func(ctx context.Context) ([]camunda.Cluster, error)
+	MockGetCluster    func(ctx context.Context, id string) (*camunda.Cluster, error)
+	MockCreateCluster func(ctx context.Context, s camunda.ClusterSpec) (string, error)
+	MockDeleteCluster func(ctx context.Context, id string) error
+
+	MockListClients  func(ctx context.Context, clusterID string) ([]camunda.Client, error)
+	MockGetClient    func(ctx context.Context, clusterID, clientID string) (*camunda.ClientDetails, error)
+	MockCreateClient func(ctx context.Context, clusterID, name string) (*camunda.ClientCredentials, error)
+	MockDeleteClient func(ctx context.Context, clusterID, clientID string) error
+
+	MockGetParameters func(ctx context.Context) (*camunda.Parameters, error)
+}
+
+// ListClusters calls MockListClusters.
+func (m *MockAPI) ListClusters(ctx context.Context) ([]camunda.Cluster, error) {
+	return m.MockListClusters(ctx)
+}
+
+// GetCluster calls MockGetCluster.
+func (m *MockAPI) GetCluster(ctx context.Context, id string) (*camunda.Cluster, error) {
+	return m.MockGetCluster(ctx, id)
+}
+
+// CreateCluster calls MockCreateCluster.
+func (m *MockAPI) CreateCluster(ctx context.Context, s camunda.ClusterSpec) (string, error) {
+	return m.MockCreateCluster(ctx, s)
+}
+
+// DeleteCluster calls MockDeleteCluster.
+func (m *MockAPI) DeleteCluster(ctx context.Context, id string) error {
+	return m.MockDeleteCluster(ctx, id)
+}
+
+// ListClients calls MockListClients.
+func (m *MockAPI) ListClients(ctx context.Context, clusterID string) ([]camunda.Client, error) {
+	return m.MockListClients(ctx, clusterID)
+}
+
+// GetClient calls MockGetClient.
+func (m *MockAPI) GetClient(ctx context.Context, clusterID, clientID string) (*camunda.ClientDetails, error) {
+	return m.MockGetClient(ctx, clusterID, clientID)
+}
+
+// CreateClient calls MockCreateClient.
+func (m *MockAPI) CreateClient(ctx context.Context, clusterID, name string) (*camunda.ClientCredentials, error) {
+	return m.MockCreateClient(ctx, clusterID, name)
+}
+
+// DeleteClient calls MockDeleteClient.
+func (m *MockAPI) DeleteClient(ctx context.Context, clusterID, clientID string) error {
+	return m.MockDeleteClient(ctx, clusterID, clientID)
+}
+
+// GetParameters calls MockGetParameters.
+func (m *MockAPI) GetParameters(ctx context.Context) (*camunda.Parameters, error) {
+	return m.MockGetParameters(ctx)
+}
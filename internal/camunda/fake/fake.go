@@ -0,0 +1,601 @@
+// Package fake provides an in-memory stand-in for the Camunda Console API and
+// its authorization server, for tests and local development.
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	console "github.com/sijoma/console-customer-api-go"
+
+	"github.com/crossplane/provider-camunda/internal/metrics"
+)
+
+// Credentials and IDs the Console is populated with.
+const (
+	ClientID       = "fake-client-id"
+	ClientSecret   = "fake-client-secret"
+	OrganizationID = "fake-organization"
+
+	ChannelID    = "6bdcf0e5-ff1e-4f7c-a5a3-0b6a1e7f2b10"
+	GenerationID = "0b3c7a43-1c8e-4d5b-9fcd-3f3c3d0e8a21"
+	RegionID     = "2f6c1d8e-9b7a-4c3e-8d2f-5a4b3c2d1e01"
+	PlanTypeID   = "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+
+	region = "fake-1"
+)
+
+// TokenPath is the path of the Console's authorization server.
+const TokenPath = "/oauth/token"
+
+// DefaultParameters are the cluster parameters a Console offers unless it is
+// configured with others.
+var DefaultParameters = console.Parameters{
+	Channels: []console.ParametersChannelsInner{{
+		Name:               "Stable",
+		Uuid:               ChannelID,
+		DefaultGeneration:  console.ParametersChannelsInnerAllowedGenerationsInner{Name: "Zeebe 8.2.2", Uuid: GenerationID},
+		AllowedGenerations: []console.ParametersChannelsInnerAllowedGenerationsInner{{Name: "Zeebe 8.2.2", Uuid: GenerationID}},
+	}},
+	Regions:          []console.ParametersChannelsInnerAllowedGenerationsInner{{Name: "Fake Region", Uuid: RegionID}},
+	ClusterPlanTypes: []console.ParametersChannelsInnerAllowedGenerationsInner{{Name: "Trial Package", Uuid: PlanTypeID}},
+}
+
+// A Fault is injected into the responses of a Console.
+type Fault struct {
+	// Latency delays the response.
+	Latency time.Duration
+
+	// StatusCode replaces the response with an error of this status, for
+	// example 404, 429 or 500. No error is returned if it is zero.
+	StatusCode int
+
+	// Count is the number of requests the fault applies to. It applies to all
+	// requests if it is zero.
+	Count int
+}
+
+type fault struct {
+	Fault
+	endpoint string
+}
+
+// A Console is an http.Handler that serves the Console API and an
+// authorization server from memory. Clusters are created healthy, unless the
+// Console is configured to provision them, and clients are created
+// immediately.
+type Console struct {
+	mu           sync.Mutex
+	now          func() time.Time
+	organization string
+	token        string
+	tokenExpiry  time.Duration
+	parameters   console.Parameters
+	provisioning time.Duration
+	clusters     map[string]*console.Cluster
+	healthyAt    map[string]time.Time
+	order        []string
+	clients      map[string][]console.CreatedClusterClient
+	faults       []*fault
+	requests     map[string]int
+}
+
+// An Option configures a Console.
+type Option func(s *Console)
+
+// WithParameters configures the cluster parameters a Console offers.
+func WithParameters(p console.Parameters) Option {
+	return func(s *Console) {
+		s.parameters = p
+	}
+}
+
+// WithClusters configures the clusters a Console starts with.
+func WithClusters(cs ...console.Cluster) Option {
+	return func(s *Console) {
+		for i := range cs {
+			s.addCluster(cs[i])
+		}
+	}
+}
+
+// WithOrganization configures the ID of the organization the access tokens of
+// a Console belong to.
+func WithOrganization(id string) Option {
+	return func(s *Console) {
+		s.organization = id
+	}
+}
+
+// WithTokenLifetime configures how long the access tokens of a Console are
+// valid for.
+func WithTokenLifetime(d time.Duration) Option {
+	return func(s *Console) {
+		s.tokenExpiry = d
+	}
+}
+
+// WithProvisioning configures a Console to create clusters that are Creating
+// for the supplied duration before they become Healthy.
+func WithProvisioning(d time.Duration) Option {
+	return func(s *Console) {
+		s.provisioning = d
+	}
+}
+
+// NewConsole returns a Console with no clusters.
+func NewConsole(o ...Option) *Console {
+	s := &Console{
+		now:          time.Now,
+		organization: OrganizationID,
+		tokenExpiry:  time.Hour,
+		parameters:   DefaultParameters,
+		clusters:     map[string]*console.Cluster{},
+		healthyAt:    map[string]time.Time{},
+		clients:      map[string][]console.CreatedClusterClient{},
+		requests:     map[string]int{},
+	}
+	for _, fn := range o {
+		fn(s)
+	}
+	claims, _ := json.Marshal(map[string]string{"sub": ClientID, "https://camunda.com/orgId": s.organization})
+	s.token = "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(claims) + ".fake"
+	return s
+}
+
+// A Server serves a Console on a local port.
+type Server struct {
+	*httptest.Server
+	*Console
+}
+
+// NewServer starts a Server. It must be closed by the caller.
+func NewServer(o ...Option) *Server {
+	c := NewConsole(o...)
+	return &Server{Server: httptest.NewServer(c), Console: c}
+}
+
+// Credentials returns credentials for a ProviderConfig that authenticate to
+// the Server.
+func (s *Server) Credentials() []byte {
+	return s.CredentialsFor(s.URL)
+}
+
+// CredentialsFor returns credentials for a ProviderConfig that authenticate to
+// the Console when it is served at the supplied URL.
+func (s *Console) CredentialsFor(url string) []byte {
+	creds, _ := json.Marshal(map[string]string{
+		"client_id":     ClientID,
+		"client_secret": ClientSecret,
+		"token_url":     url + TokenPath,
+		"api_url":       url,
+	})
+	return creds
+}
+
+// Inject a fault into the responses to the supplied endpoint, for example
+// "GET /clusters/{id}". IDs in the endpoint are written as {id}, as in the
+// endpoint label of the Console API metrics. A fault for the empty endpoint
+// applies to all requests, including those for tokens.
+func (s *Console) Inject(endpoint string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{Fault: f, endpoint: endpoint})
+}
+
+// A state is what a Console saves.
+type state struct {
+	Clusters  []console.Cluster                         `json:"clusters"`
+	Clients   map[string][]console.CreatedClusterClient `json:"clients,omitempty"`
+	HealthyAt map[string]time.Time                      `json:"healthyAt,omitempty"`
+}
+
+// Save writes the clusters and clients of the Console to the supplied writer.
+func (s *Console) Save(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := state{Clusters: make([]console.Cluster, 0, len(s.order)), Clients: s.clients, HealthyAt: s.healthyAt}
+	for _, id := range s.order {
+		st.Clusters = append(st.Clusters, *s.clusters[id])
+	}
+	return json.NewEncoder(w).Encode(st)
+}
+
+// Load replaces the clusters and clients of the Console with those saved to
+// the supplied reader.
+func (s *Console) Load(r io.Reader) error {
+	st := state{}
+	if err := json.NewDecoder(r).Decode(&st); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clusters, s.order = map[string]*console.Cluster{}, nil
+	for i := range st.Clusters {
+		s.addCluster(st.Clusters[i])
+	}
+	s.clients = map[string][]console.CreatedClusterClient{}
+	for id, l := range st.Clients {
+		s.clients[id] = l
+	}
+	s.healthyAt = map[string]time.Time{}
+	for id, t := range st.HealthyAt {
+		s.healthyAt[id] = t
+	}
+	return nil
+}
+
+// ClearFaults removes all injected faults.
+func (s *Console) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the number of requests the Console received for the
+// supplied endpoint.
+func (s *Console) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// AllRequests returns the number of requests the Console received for each
+// endpoint.
+func (s *Console) AllRequests() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := make(map[string]int, len(s.requests))
+	for e, n := range s.requests {
+		r[e] = n
+	}
+	return r
+}
+
+// AddCluster adds the supplied cluster. A missing ID is generated, and the
+// cluster's links are derived from its ID unless they are set. It returns the
+// ID of the cluster.
+func (s *Console) AddCluster(c console.Cluster) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addCluster(c)
+}
+
+// Cluster returns the cluster with the supplied ID.
+func (s *Console) Cluster(id string) (console.Cluster, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.clusters[id]
+	if !ok {
+		return console.Cluster{}, false
+	}
+	return *c, true
+}
+
+// SetClusterStatus sets the status of the cluster with the supplied ID.
+func (s *Console) SetClusterStatus(id string, st console.ClusterStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.clusters[id]; ok {
+		c.Status = st
+	}
+}
+
+// AddClient adds a client with the supplied name to the cluster with the
+// supplied ID, and returns it.
+func (s *Console) AddClient(clusterID, name string) console.CreatedClusterClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addClient(clusterID, console.CreateClusterClientBody{ClientName: name})
+}
+
+// Clients returns the clients of the cluster with the supplied ID.
+func (s *Console) Clients(clusterID string) []console.CreatedClusterClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]console.CreatedClusterClient(nil), s.clients[clusterID]...)
+}
+
+// HealthyStatus returns the status of a cluster whose components are all
+// healthy.
+func HealthyStatus() console.ClusterStatus {
+	return Status(console.HEALTHY)
+}
+
+// Status returns the status of a cluster whose components all have the
+// supplied health.
+func Status(h console.ClusterHealthStatus) console.ClusterStatus {
+	return console.ClusterStatus{
+		Ready:          h,
+		ZeebeStatus:    &h,
+		OperateStatus:  &h,
+		TasklistStatus: &h,
+		OptimizeStatus: &h,
+	}
+}
+
+func (s *Console) addCluster(c console.Cluster) string {
+	if c.Uuid == "" {
+		c.Uuid = uuid.NewString()
+	}
+	if c.Links.Zeebe == nil {
+		c.Links = links(c.Uuid)
+	}
+	if c.Status.Ready == "" {
+		c.Status = HealthyStatus()
+	}
+	if _, ok := s.clusters[c.Uuid]; !ok {
+		s.order = append(s.order, c.Uuid)
+	}
+	s.clusters[c.Uuid] = &c
+	return c.Uuid
+}
+
+func links(id string) console.ClusterLinks {
+	str := func(s string) *string { return &s }
+	return console.ClusterLinks{
+		Zeebe:    str(fmt.Sprintf("%s.%s.zeebe.camunda.io", id, region)),
+		Operate:  str(fmt.Sprintf("https://%s.operate.camunda.io/%s", region, id)),
+		Tasklist: str(fmt.Sprintf("https://%s.tasklist.camunda.io/%s", region, id)),
+		Optimize: str(fmt.Sprintf("https://%s.optimize.camunda.io/%s", region, id)),
+	}
+}
+
+// ServeHTTP serves a request for the Console API or its authorization server.
+func (s *Console) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := metrics.Endpoint(r)
+	s.mu.Lock()
+	s.requests[endpoint]++
+	f := s.fault(endpoint)
+	s.mu.Unlock()
+
+	if f.Latency > 0 {
+		select {
+		case <-time.After(f.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if f.StatusCode != 0 {
+		if f.StatusCode == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeError(w, f.StatusCode)
+		return
+	}
+
+	if r.URL.Path == TokenPath {
+		s.serveToken(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+s.token {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provision()
+	s.route(w, r, strings.Split(strings.Trim(r.URL.Path, "/"), "/"))
+}
+
+// provision marks the clusters whose provisioning is done as healthy. It must
+// be called with the lock held.
+func (s *Console) provision() {
+	for id, t := range s.healthyAt {
+		if s.now().Before(t) {
+			continue
+		}
+		if c, ok := s.clusters[id]; ok {
+			c.Status = HealthyStatus()
+		}
+		delete(s.healthyAt, id)
+	}
+}
+
+// fault returns the fault to inject into a response to the supplied endpoint,
+// if any. It must be called with the lock held.
+func (s *Console) fault(endpoint string) Fault {
+	for i, f := range s.faults {
+		if f.endpoint != "" && f.endpoint != endpoint {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f.Fault
+	}
+	return Fault{}
+}
+
+func (s *Console) serveToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if r.Method != http.MethodPost || id != ClientID || secret != ClientSecret {
+		writeError(w, http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": s.token,
+		"token_type":   "Bearer",
+		"expires_in":   int(s.tokenExpiry.Seconds()),
+	})
+}
+
+// route serves a Console API request. It must be called with the lock held.
+func (s *Console) route(w http.ResponseWriter, r *http.Request, path []string) { //nolint:gocyclo // A flat switch is easiest to follow.
+	switch {
+	case len(path) == 1 && path[0] == "clusters" && r.Method == http.MethodGet:
+		l := make([]console.Cluster, 0, len(s.order))
+		for _, id := range s.order {
+			l = append(l, *s.clusters[id])
+		}
+		writeJSON(w, http.StatusOK, l)
+	case len(path) == 1 && path[0] == "clusters" && r.Method == http.MethodPost:
+		s.createCluster(w, r)
+	case len(path) == 2 && path[0] == "clusters" && path[1] == "parameters" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.parameters)
+	case len(path) < 2 || path[0] != "clusters":
+		writeError(w, http.StatusNotFound)
+	case s.clusters[path[1]] == nil:
+		writeError(w, http.StatusNotFound)
+	case len(path) == 2 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.clusters[path[1]])
+	case len(path) == 2 && r.Method == http.MethodDelete:
+		delete(s.clusters, path[1])
+		delete(s.clients, path[1])
+		delete(s.healthyAt, path[1])
+		for i, id := range s.order {
+			if id == path[1] {
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(path) == 3 && path[2] == "clients" && r.Method == http.MethodGet:
+		l := make([]console.ClusterClient, 0, len(s.clients[path[1]]))
+		for _, c := range s.clients[path[1]] {
+			l = append(l, console.ClusterClient{Name: c.Name, ClientId: c.ClientId, Permissions: c.Permissions})
+		}
+		writeJSON(w, http.StatusOK, l)
+	case len(path) == 3 && path[2] == "clients" && r.Method == http.MethodPost:
+		s.createClient(w, r, path[1])
+	case len(path) == 4 && path[2] == "clients":
+		s.serveClient(w, r, path[1], path[3])
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+func (s *Console) createCluster(w http.ResponseWriter, r *http.Request) {
+	b := console.CreateClusterBody{}
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil || b.Name == "" {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	channel, ok := find(s.parameters.Channels, b.ChannelId)
+	if !ok {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	generation, okg := findInner(channel.AllowedGenerations, b.GenerationId)
+	reg, okr := findInner(s.parameters.Regions, b.RegionId)
+	plan, okp := findInner(s.parameters.ClusterPlanTypes, b.PlanTypeId)
+	if !okg || !okr || !okp {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	c := console.Cluster{
+		Name:       b.Name,
+		OwnerId:    ClientID,
+		Created:    s.now(),
+		Channel:    console.ClusterChannel{Name: channel.Name, Uuid: channel.Uuid},
+		Generation: console.ClusterGeneration{Name: generation.Name, Uuid: generation.Uuid},
+		Region:     console.ClusterRegion{Name: reg.Name, Uuid: reg.Uuid},
+		PlanType:   console.ClusterPlanType{Name: plan.Name, Uuid: plan.Uuid},
+	}
+	if s.provisioning > 0 {
+		c.Status = Status(console.CREATING)
+	}
+	id := s.addCluster(c)
+	if s.provisioning > 0 {
+		s.healthyAt[id] = s.now().Add(s.provisioning)
+	}
+	writeJSON(w, http.StatusOK, console.CreateCluster200Response{ClusterId: id})
+}
+
+func (s *Console) createClient(w http.ResponseWriter, r *http.Request, clusterID string) {
+	b := console.CreateClusterClientBody{}
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil || b.ClientName == "" {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.addClient(clusterID, b))
+}
+
+func (s *Console) addClient(clusterID string, b console.CreateClusterClientBody) console.CreatedClusterClient {
+	c := console.CreatedClusterClient{
+		Name:         b.ClientName,
+		Uuid:         uuid.NewString(),
+		ClientId:     strings.ReplaceAll(uuid.NewString(), "-", "")[:24],
+		ClientSecret: strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Permissions:  b.Permissions,
+	}
+	s.clients[clusterID] = append(s.clients[clusterID], c)
+	return c
+}
+
+func (s *Console) serveClient(w http.ResponseWriter, r *http.Request, clusterID, clientID string) {
+	l := s.clients[clusterID]
+	for i, c := range l {
+		if c.ClientId != clientID {
+			continue
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, console.ClusterClientConnectionDetails{
+				Name:                           c.Name,
+				ZEEBE_ADDRESS:                  s.clusters[clusterID].Links.GetZeebe() + ":443",
+				ZEEBE_CLIENT_ID:                c.ClientId,
+				ZEEBE_AUTHORIZATION_SERVER_URL: baseURL(r) + TokenPath,
+			})
+		case http.MethodDelete:
+			s.clients[clusterID] = append(l[:i], l[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed)
+		}
+		return
+	}
+	writeError(w, http.StatusNotFound)
+}
+
+func find(l []console.ParametersChannelsInner, id string) (console.ParametersChannelsInner, bool) {
+	for _, e := range l {
+		if e.Uuid == id {
+			return e, true
+		}
+	}
+	return console.ParametersChannelsInner{}, false
+}
+
+func findInner(l []console.ParametersChannelsInnerAllowedGenerationsInner, id string) (console.ParametersChannelsInnerAllowedGenerationsInner, bool) {
+	for _, e := range l {
+		if e.Uuid == id {
+			return e, true
+		}
+	}
+	return console.ParametersChannelsInnerAllowedGenerationsInner{}, false
+}
+
+// baseURL returns the URL the supplied request was sent to, without its path.
+func baseURL(r *http.Request) string {
+	if r.TLS != nil {
+		return "https://" + r.Host
+	}
+	return "http://" + r.Host
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int) {
+	writeJSON(w, code, map[string]string{"message": strconv.Itoa(code) + " " + http.StatusText(code)})
+}
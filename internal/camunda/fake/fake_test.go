@@ -0,0 +1,330 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	console "github.com/sijoma/console-customer-api-go"
+
+	"github.com/crossplane/provider-camunda/internal/camunda"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	svc, err := camunda.Authenticate(ctx, srv.Credentials())
+	if err != nil {
+		t.Fatalf("camunda.Authenticate(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(OrganizationID, camunda.OrganizationID(accessToken(t, svc))); diff != "" {
+		t.Errorf("\nThe access token should carry the organization ID.\nAuthenticate(...): -want, +got:\n%s\n", diff)
+	}
+
+	id, err := svc.CreateCluster(ctx, camunda.ClusterSpec{
+		Name:       "cool-cluster",
+		Channel:    ChannelID,
+		Generation: GenerationID,
+		Region:     RegionID,
+		PlanType:   PlanTypeID,
+	})
+	if err != nil {
+		t.Fatalf("CreateCluster(...): unexpected error: %v", err)
+	}
+
+	got, err := svc.GetCluster(ctx, id)
+	if err != nil {
+		t.Fatalf("GetCluster(...): unexpected error: %v", err)
+	}
+	want := camunda.ClusterStatus{Ready: camunda.Healthy, Zeebe: camunda.Healthy, Operate: camunda.Healthy, Tasklist: camunda.Healthy, Optimize: camunda.Healthy}
+	if diff := cmp.Diff(want, got.Status); diff != "" {
+		t.Errorf("\nA new cluster should be healthy.\nGetCluster(...): -want, +got:\n%s\n", diff)
+	}
+
+	cl, err := svc.CreateClient(ctx, id, "cool-client")
+	if err != nil {
+		t.Fatalf("CreateClient(...): unexpected error: %v", err)
+	}
+	d, err := svc.GetClient(ctx, id, cl.ID)
+	if err != nil {
+		t.Fatalf("GetClient(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(got.Endpoints.Zeebe+":443", d.ZeebeAddress); diff != "" {
+		t.Errorf("\nA client should connect to the Zeebe gateway of its cluster.\nGetClient(...): -want, +got:\n%s\n", diff)
+	}
+
+	if err := svc.DeleteCluster(ctx, id); err != nil {
+		t.Fatalf("DeleteCluster(...): unexpected error: %v", err)
+	}
+	if _, err := svc.GetCluster(ctx, id); !camunda.IsNotFound(err) {
+		t.Errorf("\nA deleted cluster should not be found.\nGetCluster(...): got error %v", err)
+	}
+}
+
+func TestServerRejects(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.WithValue(context.Background(), console.ContextAccessToken, "not-the-token")
+	svc, err := camunda.Authenticate(context.Background(), srv.Credentials())
+	if err != nil {
+		t.Fatalf("camunda.Authenticate(...): unexpected error: %v", err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		call   func() (*http.Response, error)
+		want   int
+	}{
+		"WrongToken": {
+			reason: "Requests with a token the Server did not issue should be rejected.",
+			call: func() (*http.Response, error) {
+				_, resp, err := svc.ClustersApi.GetClusters(ctx).Execute()
+				return resp, err
+			},
+			want: http.StatusUnauthorized,
+		},
+		"UnknownParameters": {
+			reason: "Clusters with parameters the Server does not offer should be rejected.",
+			call: func() (*http.Response, error) {
+				ctx := context.WithValue(context.Background(), console.ContextAccessToken, accessToken(t, svc))
+				_, resp, err := svc.ClustersApi.CreateCluster(ctx).CreateClusterBody(console.CreateClusterBody{Name: "cool-cluster", ChannelId: "unknown"}).Execute()
+				return resp, err
+			},
+			want: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp, err := tc.call()
+			if err == nil {
+				t.Fatalf("\n%s\nexpected an error", tc.reason)
+			}
+			if diff := cmp.Diff(tc.want, resp.StatusCode); diff != "" {
+				t.Errorf("\n%s\n-want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		endpoint string
+		fault    Fault
+		want     []int
+	}{
+		"Once": {
+			reason:   "A fault with a count should only apply to that many requests.",
+			endpoint: "GET /clusters",
+			fault:    Fault{StatusCode: http.StatusTooManyRequests, Count: 1},
+			want:     []int{http.StatusTooManyRequests, http.StatusOK},
+		},
+		"Always": {
+			reason:   "A fault without a count should apply to all requests.",
+			endpoint: "GET /clusters",
+			fault:    Fault{StatusCode: http.StatusInternalServerError},
+			want:     []int{http.StatusInternalServerError, http.StatusInternalServerError},
+		},
+		"OtherEndpoint": {
+			reason:   "A fault should not apply to other endpoints.",
+			endpoint: "GET /clusters/{id}",
+			fault:    Fault{StatusCode: http.StatusNotFound},
+			want:     []int{http.StatusOK, http.StatusOK},
+		},
+		"Latency": {
+			reason: "A fault without a status should only delay responses.",
+			fault:  Fault{Latency: time.Millisecond},
+			want:   []int{http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := NewServer()
+			defer srv.Close()
+			svc, err := camunda.Authenticate(context.Background(), srv.Credentials())
+			if err != nil {
+				t.Fatalf("camunda.Authenticate(...): unexpected error: %v", err)
+			}
+			ctx := context.WithValue(context.Background(), console.ContextAccessToken, accessToken(t, svc))
+
+			srv.Inject(tc.endpoint, tc.fault)
+			got := []int{}
+			for range tc.want {
+				_, resp, _ := svc.ClustersApi.GetClusters(ctx).Execute()
+				got = append(got, resp.StatusCode)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetClusters(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(len(tc.want), srv.Requests("GET /clusters")); diff != "" {
+				t.Errorf("\n%s\nsrv.Requests(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProvisioning(t *testing.T) {
+	now := time.Now()
+	c := NewConsole(WithProvisioning(time.Minute))
+	c.now = func() time.Time { return now }
+	srv := &Server{Server: httptest.NewServer(c), Console: c}
+	defer srv.Close()
+
+	ctx := context.Background()
+	svc, err := camunda.Authenticate(ctx, srv.Credentials())
+	if err != nil {
+		t.Fatalf("camunda.Authenticate(...): unexpected error: %v", err)
+	}
+	id, err := svc.CreateCluster(ctx, camunda.ClusterSpec{Name: "cool-cluster", Channel: ChannelID, Generation: GenerationID, Region: RegionID, PlanType: PlanTypeID})
+	if err != nil {
+		t.Fatalf("CreateCluster(...): unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		reason string
+		after  time.Duration
+		want   camunda.HealthStatus
+	}{
+		{
+			name:   "Provisioning",
+			reason: "A new cluster should be creating while it is provisioned.",
+			after:  time.Second,
+			want:   camunda.Creating,
+		},
+		{
+			name:   "Provisioned",
+			reason: "A cluster should be healthy once it is provisioned.",
+			after:  time.Minute,
+			want:   camunda.Healthy,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c.mu.Lock()
+			c.now = func() time.Time { return now.Add(tc.after) }
+			c.mu.Unlock()
+			got, err := svc.GetCluster(ctx, id)
+			if err != nil {
+				t.Fatalf("GetCluster(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Status.Zeebe); diff != "" {
+				t.Errorf("\n%s\nGetCluster(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	saved := NewConsole()
+	id := saved.AddCluster(console.Cluster{Name: "cool-cluster"})
+	cl := saved.AddClient(id, "cool-client")
+
+	b := &bytes.Buffer{}
+	if err := saved.Save(b); err != nil {
+		t.Fatalf("Save(...): unexpected error: %v", err)
+	}
+	loaded := NewConsole()
+	if err := loaded.Load(b); err != nil {
+		t.Fatalf("Load(...): unexpected error: %v", err)
+	}
+
+	want, _ := saved.Cluster(id)
+	got, ok := loaded.Cluster(id)
+	if !ok {
+		t.Fatalf("\nA loaded Console should have the saved clusters.\nCluster(...): cluster %s does not exist", id)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nA loaded Console should have the saved clusters.\nCluster(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]console.CreatedClusterClient{cl}, loaded.Clients(id)); diff != "" {
+		t.Errorf("\nA loaded Console should have the saved clients.\nClients(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+// accessToken returns the current access token of the supplied Service.
+func accessToken(t *testing.T, svc *camunda.Service) string {
+	t.Helper()
+	tok, err := svc.Token()
+	if err != nil {
+		t.Fatalf("svc.Token(): unexpected error: %v", err)
+	}
+	return tok.AccessToken
+}
+
+func TestNewService(t *testing.T) {
+	acme := NewServer(WithOrganization("acme"), WithClusters(console.Cluster{Uuid: "acme-cluster", Name: "acme"}))
+	defer acme.Close()
+	initech := NewServer(WithOrganization("initech"), WithClusters(console.Cluster{Uuid: "initech-cluster", Name: "initech"}))
+	defer initech.Close()
+
+	ctx := context.Background()
+	names := func(key string, creds []byte) []string {
+		t.Helper()
+		svc, err := camunda.NewService(ctx, key, creds)
+		if err != nil {
+			t.Fatalf("camunda.NewService(%s): unexpected error: %v", key, err)
+		}
+		l, err := svc.ListClusters(ctx)
+		if err != nil {
+			t.Fatalf("ListClusters(...): unexpected error: %v", err)
+		}
+		out := make([]string, len(l))
+		for i := range l {
+			out[i] = l[i].Name
+		}
+		return out
+	}
+
+	if diff := cmp.Diff([]string{"acme"}, names("acme", acme.Credentials())); diff != "" {
+		t.Errorf("\nA ProviderConfig should work in the organization of its credentials.\nListClusters(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"initech"}, names("tenant/initech", initech.Credentials())); diff != "" {
+		t.Errorf("\nAnother ProviderConfig should work in the organization of its own credentials.\nListClusters(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"acme"}, names("acme", acme.Credentials())); diff != "" {
+		t.Errorf("\nA ProviderConfig should keep its organization once others connected.\nListClusters(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(1, acme.Requests("POST "+TokenPath)); diff != "" {
+		t.Errorf("\nThe Service of a ProviderConfig should be reused while its credentials do not change.\nsrv.Requests(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"initech"}, names("acme", initech.Credentials())); diff != "" {
+		t.Errorf("\nA ProviderConfig whose credentials changed should work in the organization of the new credentials.\nListClusters(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	// Tokens are refreshed shortly before they expire, so a token that is
+	// valid for a second is refreshed before every call.
+	srv := NewServer(WithTokenLifetime(time.Second))
+	defer srv.Close()
+
+	ctx := context.Background()
+	svc, err := camunda.Authenticate(ctx, srv.Credentials())
+	if err != nil {
+		t.Fatalf("camunda.Authenticate(...): unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ListClusters(ctx); err != nil {
+			t.Fatalf("ListClusters(...): unexpected error: %v", err)
+		}
+	}
+	if diff := cmp.Diff(3, srv.Requests("POST "+TokenPath)); diff != "" {
+		t.Errorf("\nAn expired access token should be refreshed.\nsrv.Requests(...): -want, +got:\n%s\n", diff)
+	}
+
+	srv.Inject("POST "+TokenPath, Fault{StatusCode: http.StatusUnauthorized})
+	if _, err := svc.ListClusters(ctx); !camunda.IsUnauthorized(err) {
+		t.Errorf("\nA rejected refresh should be unauthorized.\nListClusters(...): got error %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package camunda
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// redacted replaces the values of sensitive fields in recorded bodies.
+const redacted = "REDACTED"
+
+// sensitiveFields are the fields of request and response bodies whose values
+// are never recorded.
+var sensitiveFields = map[string]bool{
+	"access_token":        true,
+	"clientSecret":        true,
+	"client_secret":       true,
+	"ZEEBE_CLIENT_SECRET": true,
+	"ownerId":             true,
+}
+
+// An Interaction is a Console API request and the response to it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// A RecordedRequest is a Console API request, without its headers.
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// A RecordedResponse is a Console API response, without its headers.
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// A Recorder is an http.RoundTripper that records the requests it sends and
+// the responses to them. Headers and the values of sensitive fields are not
+// recorded, so that recordings can be committed.
+type Recorder struct {
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder that sends requests with the supplied
+// RoundTripper.
+func NewRecorder(next http.RoundTripper) *Recorder {
+	return &Recorder{next: next}
+}
+
+// RoundTrip sends the supplied request and records it and its response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	// The organization ID turns up in other fields, for example in the
+	// console links of clusters, so it is redacted wherever it appears.
+	org := OrganizationID(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request:  RecordedRequest{Method: req.Method, Path: redactString(req.URL.RequestURI(), org), Body: sanitize(reqBody, org)},
+		Response: RecordedResponse{Status: resp.StatusCode, Body: sanitize(respBody, org)},
+	})
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the supplied file.
+func (r *Recorder) Save(file string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(b, '\n'), 0o600)
+}
+
+// sanitize returns the supplied JSON body with the values of sensitive fields
+// and all occurrences of the supplied organization ID redacted. Bodies that
+// are not JSON are not recorded.
+func sanitize(body []byte, org string) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil
+	}
+	b, err := json.Marshal(redact(v, org))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func redact(v any, org string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if sensitiveFields[k] {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redact(e, org)
+		}
+	case []any:
+		for i := range v {
+			v[i] = redact(v[i], org)
+		}
+	case string:
+		return redactString(v, org)
+	}
+	return v
+}
+
+// redactString returns the supplied string with all occurrences of the
+// supplied organization ID redacted.
+func redactString(s, org string) string {
+	if org == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, org, redacted)
+}
+
+// A Replayer is an http.RoundTripper that answers requests with recorded
+// responses instead of sending them. Each recorded interaction answers one
+// request with the same method, path and query.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// LoadReplayer returns a Replayer for the interactions recorded to the
+// supplied file.
+func LoadReplayer(file string) (*Replayer, error) {
+	b, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	r := &Replayer{}
+	if err := json.Unmarshal(b, &r.interactions); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse recording %s", file)
+	}
+	r.replayed = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// RoundTrip answers the supplied request with the first recorded response to
+// the same request that was not replayed yet.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.replayed[i] || in.Request.Method != req.Method || in.Request.Path != req.URL.RequestURI() {
+			continue
+		}
+		r.replayed[i] = true
+		return &http.Response{
+			Status:        http.StatusText(in.Response.Status),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, errors.Errorf("no recorded response to %s %s", req.Method, req.URL.RequestURI())
+}
+
+// Unreplayed returns the recorded requests that were not replayed.
+func (r *Replayer) Unreplayed() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var l []RecordedRequest
+	for i, in := range r.interactions {
+		if !r.replayed[i] {
+			l = append(l, in.Request)
+		}
+	}
+	return l
+}
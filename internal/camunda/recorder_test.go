@@ -0,0 +1,123 @@
+package camunda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	console "github.com/sijoma/console-customer-api-go"
+	"golang.org/x/oauth2"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(console.CreatedClusterClient{Name: "worker", ClientId: "worker-client", ClientSecret: "s3cr3t"})
+	}))
+	defer srv.Close()
+
+	r := NewRecorder(http.DefaultTransport)
+	c, err := newService(srv.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "t0k3n"}), r).CreateClient(context.Background(), "cluster", "worker")
+	if err != nil {
+		t.Fatalf("s.CreateClient(...): %v", err)
+	}
+	if diff := cmp.Diff(&ClientCredentials{ID: "worker-client", Secret: "s3cr3t"}, c); diff != "" {
+		t.Errorf("\nRecording should not change the response.\ns.CreateClient(...): -want, +got:\n%s\n", diff)
+	}
+
+	file := filepath.Join(t.TempDir(), "recording.json")
+	if err := r.Save(file); err != nil {
+		t.Fatalf("r.Save(...): %v", err)
+	}
+	rp, err := LoadReplayer(file)
+	if err != nil {
+		t.Fatalf("LoadReplayer(...): %v", err)
+	}
+
+	want := []Interaction{{
+		Request: RecordedRequest{
+			Method: http.MethodPost,
+			Path:   "/clusters/cluster/clients",
+			Body:   json.RawMessage(`{"clientName":"worker"}`),
+		},
+		Response: RecordedResponse{
+			Status: http.StatusOK,
+			Body:   json.RawMessage(`{"clientId":"worker-client","clientSecret":"REDACTED","name":"worker","permissions":null,"uuid":""}`),
+		},
+	}}
+	// Compare bodies by their content, not their indentation.
+	body := cmp.Transformer("JSON", func(m json.RawMessage) any {
+		var v any
+		_ = json.Unmarshal(m, &v)
+		return v
+	})
+	if diff := cmp.Diff(want, rp.interactions, body); diff != "" {
+		t.Errorf("\nThe recording should hold the interaction without secrets.\nr.Save(...): -want, +got:\n%s\n", diff)
+	}
+
+	s := newService("https://console.invalid", oauth2.StaticTokenSource(&oauth2.Token{}), rp)
+	c, err = s.CreateClient(context.Background(), "cluster", "worker")
+	if err != nil {
+		t.Fatalf("s.CreateClient(...): %v", err)
+	}
+	if diff := cmp.Diff(&ClientCredentials{ID: "worker-client", Secret: redacted}, c); diff != "" {
+		t.Errorf("\nThe recorded response should be replayed.\ns.CreateClient(...): -want, +got:\n%s\n", diff)
+	}
+	if _, err := s.CreateClient(context.Background(), "cluster", "worker"); err == nil {
+		t.Errorf("\nA recorded response should be replayed only once.\ns.CreateClient(...): expected an error")
+	}
+}
+
+func TestRecorderRedactsOrganization(t *testing.T) {
+	org := "5a1c9e2b-7d3f-4e8a-9b6c-0f2d4a6e8c1b"
+	token := "header." + base64.RawURLEncoding.EncodeToString([]byte(`{"`+claimOrganizationID+`":"`+org+`"}`)) + ".signature"
+
+	// A cluster as the Console API returns it, with the organization ID in
+	// its console link.
+	body := `{"uuid":"cluster-healthy","name":"healthy","ownerId":"owner","created":"2023-04-03T08:15:30.000Z",` +
+		`"planType":{"name":"Trial Cluster","uuid":"plan-trial"},"region":{"name":"Belgium, Europe (europe-west1)","uuid":"region-europe-west1"},` +
+		`"generation":{"name":"Camunda 8.2.0","uuid":"generation-8-2-0"},"channel":{"name":"Stable","uuid":"channel-stable"},` +
+		`"status":{"ready":"Healthy","zeebeStatus":"Healthy"},` +
+		`"links":{"zeebe":"cluster-healthy.bru-2.zeebe.camunda.io:443","console":"https://console.cloud.camunda.io/org/` + org + `/cluster/cluster-healthy"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	r := NewRecorder(http.DefaultTransport)
+	if _, err := newService(srv.URL, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), r).GetCluster(context.Background(), "cluster-healthy"); err != nil {
+		t.Fatalf("s.GetCluster(...): %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "recording.json")
+	if err := r.Save(file); err != nil {
+		t.Fatalf("r.Save(...): %v", err)
+	}
+	b, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		t.Fatalf("os.ReadFile(...): %v", err)
+	}
+	if strings.Contains(string(b), org) {
+		t.Errorf("\nThe recording should not hold the organization ID.\nr.Save(...): got:\n%s\n", b)
+	}
+
+	c := console.Cluster{}
+	if err := json.Unmarshal(r.interactions[0].Response.Body, &c); err != nil {
+		t.Fatalf("json.Unmarshal(...): %v", err)
+	}
+	want := "https://console.cloud.camunda.io/org/REDACTED/cluster/cluster-healthy"
+	if diff := cmp.Diff(want, c.Links.GetConsole()); diff != "" {
+		t.Errorf("\nThe organization ID should be redacted from the console link.\nr.Save(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("REDACTED", c.OwnerId); diff != "" {
+		t.Errorf("\nThe owner should be redacted.\nr.Save(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	apisv1alpha1 "github.com/crossplane/provider-camunda/apis/v1alpha1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/controller/config"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+	"github.com/crossplane/provider-camunda/internal/tracing"
+)
+
+const (
+	errNotMyType    = "managed resource is not a ClusterCatalog custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+
+	errNewClient = "cannot create new Service"
+
+	errGetParameters = "cannot get cluster parameters"
+)
+
+// Setup adds a controller that reconciles ClusterCatalogs. A ClusterCatalog is
+// refreshed at the idle interval, since the parameters the Console API offers
+// rarely change.
+func Setup(mgr ctrl.Manager, o controller.Options, pi poll.Intervals) error {
+	name := managed.ControllerName(v1beta1.ClusterCatalogGroupKind)
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1beta1.ClusterCatalogGroupVersionKind),
+		managed.WithExternalConnecter(tracing.NewConnecter(v1beta1.ClusterCatalogKind, &connector{
+			kube:         mgr.GetClient(),
+			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			newServiceFn: camunda.NewService})),
+		// A ClusterCatalog does not correspond to an external resource, so it
+		// has no external-name.
+		managed.WithInitializers(),
+		managed.WithPollInterval(pi.Idle),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ClusterCatalog{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(ctx context.Context, key string, creds []byte) (camunda.API, error)
+}
+
+// Connect produces an ExternalClient that reads the cluster parameters with
+// the credentials of the ClusterCatalog's ProviderConfig.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ClusterCatalog); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc, err := config.Resolve(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	data, err := pc.Credentials(ctx, c.kube)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(ctx, pc.Key, data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: svc}, nil
+}
+
+// An external observes the cluster parameters the Console API offers. There
+// is nothing to create, update or delete.
+type external struct {
+	service camunda.ParametersAPI
+}
+
+// Observe writes the cluster parameters the Console API offers to the status
+// of the ClusterCatalog. The catalog always exists and is up to date, unless
+// the ClusterCatalog was deleted.
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.ClusterCatalog)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	// Nothing needs to be deleted, so the finalizer can be removed at once.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	p, err := e.service.GetParameters(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetParameters)
+	}
+	*cr.GetObservation() = Observation(p)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+// Create is never called, since the catalog always exists.
+func (e *external) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, nil
+}
+
+// Update is never called, since the catalog is always up to date.
+func (e *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete does nothing, since the catalog is read only.
+func (e *external) Delete(_ context.Context, _ resource.Managed) error {
+	return nil
+}
+
+// Observation returns the supplied cluster parameters as the observation of a
+// ClusterCatalog.
+func Observation(p *camunda.Parameters) v1beta1.ClusterCatalogObservation {
+	entries := func(l []camunda.Parameter) []v1beta1.CatalogEntry {
+		out := make([]v1beta1.CatalogEntry, len(l))
+		for i := range l {
+			out[i] = v1beta1.CatalogEntry{ID: l[i].ID, Name: l[i].Name}
+		}
+		return out
+	}
+	o := v1beta1.ClusterCatalogObservation{
+		Channels:  make([]v1beta1.CatalogChannel, len(p.Channels)),
+		Regions:   entries(p.Regions),
+		PlanTypes: entries(p.PlanTypes),
+	}
+	for i, c := range p.Channels {
+		o.Channels[i] = v1beta1.CatalogChannel{
+			CatalogEntry: v1beta1.CatalogEntry{ID: c.ID, Name: c.Name},
+			Generations:  entries(c.Generations),
+		}
+		if c.DefaultGeneration.ID != "" {
+			o.Channels[i].DefaultGeneration = &v1beta1.CatalogEntry{ID: c.DefaultGeneration.ID, Name: c.DefaultGeneration.Name}
+		}
+	}
+	return o
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	console "github.com/sijoma/console-customer-api-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	apisv1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestConnect(t *testing.T) {
+	// Two organizations offer differently named regions.
+	regions := map[string]string{"acme": "Acme Region", "initech": "Initech Region"}
+	consoles := map[string]*fake.Server{}
+	for org, region := range regions {
+		p := fake.DefaultParameters
+		p.Regions = []console.ParametersChannelsInnerAllowedGenerationsInner{{Name: region, Uuid: fake.RegionID}}
+		consoles[org] = fake.NewServer(fake.WithOrganization(org), fake.WithParameters(p))
+	}
+	for _, srv := range consoles {
+		defer srv.Close()
+	}
+
+	// Each ProviderConfig reads the credentials of the organization it is
+	// named after from a Secret of the same name.
+	kube := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *apisv1beta1.ProviderConfig:
+			o.Spec.Credentials = apisv1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: key.Name},
+					Key:             "credentials",
+				}},
+			}
+		case *corev1.Secret:
+			o.Data = map[string][]byte{"credentials": consoles[key.Name].Credentials()}
+		}
+		return nil
+	}}
+	c := &connector{
+		kube:         kube,
+		usage:        resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+		newServiceFn: camunda.NewService,
+	}
+
+	for _, pc := range []string{"acme", "initech", "acme"} {
+		cr := &v1beta1.ClusterCatalog{}
+		cr.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+		e, err := c.Connect(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("c.Connect(%s): unexpected error: %v", pc, err)
+		}
+		if _, err := e.Observe(context.Background(), cr); err != nil {
+			t.Fatalf("e.Observe(%s): unexpected error: %v", pc, err)
+		}
+		want := []v1beta1.CatalogEntry{{ID: fake.RegionID, Name: regions[pc]}}
+		if diff := cmp.Diff(want, cr.Status.AtProvider.Regions); diff != "" {
+			t.Errorf("\nA ClusterCatalog should list the parameters of its own ProviderConfig's organization.\ne.Observe(%s): -want, +got:\n%s\n", pc, diff)
+		}
+	}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+	stable := camunda.Parameter{ID: fake.ChannelID, Name: "Stable"}
+	gen := camunda.Parameter{ID: fake.GenerationID, Name: "Zeebe 8.2.2"}
+	params := &camunda.Parameters{
+		Channels:  []camunda.Channel{{Parameter: stable, Generations: []camunda.Parameter{gen}, DefaultGeneration: gen}},
+		Regions:   []camunda.Parameter{{ID: fake.RegionID, Name: "Fake Region"}},
+		PlanTypes: []camunda.Parameter{{ID: fake.PlanTypeID, Name: "Trial Package"}},
+	}
+	observed := v1beta1.ClusterCatalogObservation{
+		Channels: []v1beta1.CatalogChannel{{
+			CatalogEntry:      v1beta1.CatalogEntry{ID: fake.ChannelID, Name: "Stable"},
+			DefaultGeneration: &v1beta1.CatalogEntry{ID: fake.GenerationID, Name: "Zeebe 8.2.2"},
+			Generations:       []v1beta1.CatalogEntry{{ID: fake.GenerationID, Name: "Zeebe 8.2.2"}},
+		}},
+		Regions:   []v1beta1.CatalogEntry{{ID: fake.RegionID, Name: "Fake Region"}},
+		PlanTypes: []v1beta1.CatalogEntry{{ID: fake.PlanTypeID, Name: "Trial Package"}},
+	}
+	available := &v1beta1.ClusterCatalog{Status: v1beta1.ClusterCatalogStatus{AtProvider: observed}}
+	available.SetConditions(xpv1.Available())
+	now := metav1.NewTime(time.Now())
+
+	type want struct {
+		cr  *v1beta1.ClusterCatalog
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params *camunda.Parameters
+		err    error
+		cr     *v1beta1.ClusterCatalog
+		want   want
+	}{
+		"Observed": {
+			reason: "The cluster parameters should be written to the status of an available ClusterCatalog.",
+			params: params,
+			cr:     &v1beta1.ClusterCatalog{},
+			want: want{
+				cr: available,
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"GetParametersError": {
+			reason: "Errors getting the cluster parameters should be returned.",
+			err:    errBoom,
+			cr:     &v1beta1.ClusterCatalog{},
+			want: want{
+				cr:  &v1beta1.ClusterCatalog{},
+				err: errors.Wrap(errBoom, errGetParameters),
+			},
+		},
+		"Deleted": {
+			reason: "A deleted ClusterCatalog should be reported as gone, since there is nothing to delete.",
+			cr:     &v1beta1.ClusterCatalog{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			want: want{
+				cr: &v1beta1.ClusterCatalog{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{service: &fake.MockAPI{
+				MockGetParameters: func(_ context.Context) (*camunda.Parameters, error) { return tc.params, tc.err },
+			}}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
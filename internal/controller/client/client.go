@@ -18,13 +18,14 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	console "github.com/sijoma/console-customer-api-go"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -37,9 +38,13 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
-	"github.com/crossplane/provider-camunda/apis/client/v1alpha1"
+	"github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
 	apisv1alpha1 "github.com/crossplane/provider-camunda/apis/v1alpha1"
+	"github.com/crossplane/provider-camunda/internal/controller/config"
 	"github.com/crossplane/provider-camunda/internal/controller/features"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+	"github.com/crossplane/provider-camunda/internal/tracing"
 )
 
 const (
@@ -49,11 +54,43 @@ const (
 	errGetCreds     = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
+
+	errGetClient       = "cannot get client"
+	errDeleteClient    = "cannot delete client"
+	errGetCluster      = "cannot get cluster of client"
+	errClusterNotFound = "cluster %s does not exist yet, check spec.forProvider.clusterID unless it is being created"
+	errClusterNotReady = "cluster %s is %s"
+	errClusterMissing  = "cluster %s has not existed for %s, spec.forProvider.clusterID may be wrong"
 )
 
-// Setup adds a controller that reconciles client managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.ClientGroupKind)
+// clusterMissingTimeout is how long a Client waits for its cluster to exist
+// before it reports an error. Clusters exist as soon as their creation is
+// requested, so a longer wait usually means the cluster ID is wrong.
+const clusterMissingTimeout = 10 * time.Minute
+
+// A clientResource is a Client managed resource of either scope.
+type clientResource interface {
+	resource.Managed
+	GetParameters() *v1beta1.ClientParameters
+	GetObservation() *v1beta1.ClientObservation
+}
+
+// Setup adds controllers that reconcile cluster scoped and namespaced client
+// managed resources. Clients are polled at the supplied intervals depending on
+// their state.
+func Setup(mgr ctrl.Manager, o controller.Options, pi poll.Intervals) error {
+	if err := setup(mgr, o, pi, v1beta1.ClientGroupVersionKind, func() resource.Managed { return &v1beta1.Client{} },
+		resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{})); err != nil {
+		return err
+	}
+
+	return setup(mgr, o, pi, namespacedv1beta1.ClientGroupVersionKind, func() resource.Managed { return &namespacedv1beta1.Client{} },
+		config.NewNamespacedUsageTracker(mgr.GetClient()))
+}
+
+// setup adds a controller that reconciles the supplied kind of client.
+func setup(mgr ctrl.Manager, o controller.Options, pi poll.Intervals, gvk schema.GroupVersionKind, newClient func() resource.Managed, usage resource.Tracker) error {
+	name := managed.ControllerName(gvk.GroupKind().String())
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
 	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
@@ -61,11 +98,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	}
 
 	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.ClientGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
+		resource.ManagedKind(gvk),
+		managed.WithExternalConnecter(tracing.NewConnecter(gvk.Kind, &connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: camunda.NewService}),
+			usage:        usage,
+			newServiceFn: camunda.NewService,
+			// Observations must be fresh enough for busy resources.
+			cacheTTL: pi.Busy})),
+		// The external-name of a client is the client ID assigned by the
+		// Console API on creation, so it must not default to the resource's
+		// name.
+		managed.WithInitializers(),
+		managed.WithPollInterval(o.PollInterval),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithConnectionPublishers(cps...))
@@ -73,8 +117,25 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		For(&v1alpha1.Client{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		For(newClient()).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, mgr.GetClient(), newClient, pollInterval(pi)), o.GlobalRateLimiter))
+}
+
+// pollInterval polls clients that wait for their cluster at the busy interval
+// and available clients at the idle interval.
+func pollInterval(pi poll.Intervals) poll.IntervalHook {
+	return func(mg resource.Managed, pollInterval time.Duration) time.Duration {
+		if meta.WasDeleted(mg) {
+			return pi.Busy
+		}
+		switch mg.GetCondition(xpv1.TypeReady).Reason {
+		case v1beta1.ReasonWaitingForCluster, xpv1.ReasonCreating:
+			return pi.Busy
+		case xpv1.ReasonAvailable:
+			return pi.Idle
+		}
+		return pollInterval
+	}
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
@@ -82,7 +143,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(ctx context.Context, creds []byte) (*camunda.Service, error)
+	newServiceFn func(ctx context.Context, key string, creds []byte) (camunda.API, error)
+	cacheTTL     time.Duration
 }
 
 // Connect typically produces an ExternalClient by:
@@ -91,8 +153,7 @@ type connector struct {
 // 3. Getting the credentials specified by the ProviderConfig.
 // 4. Using the credentials to form a client.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.Client)
-	if !ok {
+	if _, ok := mg.(clientResource); !ok {
 		return nil, errors.New(errNotclient)
 	}
 
@@ -100,23 +161,29 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+	pc, err := config.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	data, err := pc.Credentials(ctx, c.kube)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(ctx, data)
+	svc, err := c.newServiceFn(ctx, pc.Key, data)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: svc}, nil
+	return &external{service: svc, cache: camunda.ObservationCache(pc.Key, data, c.cacheTTL)}, nil
+}
+
+// A clientService is the part of the Console API a Client needs. Clients
+// observe the cluster they belong to, too.
+type clientService interface {
+	camunda.ClusterAPI
+	camunda.ClientAPI
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -124,40 +191,39 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *camunda.Service
+	service clientService
+	cache   *camunda.Cache
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.Client)
+	cr, ok := mg.(clientResource)
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotclient)
 	}
 
-	clientName := cr.GetName()
 	clientId := meta.GetExternalName(cr)
-	ctx = context.WithValue(ctx, console.ContextAccessToken, c.service.AccessToken)
-	inline, _, err := c.service.ClustersApi.
-		GetClient(ctx, cr.Spec.ForProvider.ClusterID, clientId).
-		Execute()
+	if clientId == "" {
+		return c.observeCluster(ctx, cr)
+	}
+
+	inline, found, err := c.cache.GetClient(ctx, c.service, cr.GetParameters().ClusterID, clientId)
 	if err != nil {
-		return managed.ExternalObservation{
-			ResourceExists:   false,
-			ResourceUpToDate: false,
-		}, nil
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetClient)
 	}
-	connectionDetails := managed.ConnectionDetails{}
-	if inline.GetName() == clientName {
-		cr.Status.SetConditions(xpv1.Available())
-	} else {
-		cr.Status.SetConditions(xpv1.Unavailable())
+	if !found {
+		return c.observeCluster(ctx, cr)
 	}
+	// The client is identified by its external-name, so it is available even
+	// if it is named differently, e.g. because it was imported.
+	connectionDetails := managed.ConnectionDetails{}
+	cr.SetConditions(xpv1.Available())
 
-	connectionDetails["ZEEBE_CLIENT_ID"] = []byte(inline.ZEEBE_CLIENT_ID)
-	connectionDetails["ZEEBE_ADDRESS"] = []byte(inline.ZEEBE_ADDRESS)
-	connectionDetails["ZEEBE_AUTHORIZATION_SERVER_URL"] = []byte(inline.ZEEBE_AUTHORIZATION_SERVER_URL)
-	cr.Status.AtProvider.ZeebeClientID = inline.ZEEBE_CLIENT_ID
-	cr.Status.AtProvider.ZeebeAddress = inline.ZEEBE_ADDRESS
-	cr.Status.AtProvider.ZeebeAuthorizationServerUrl = inline.ZEEBE_AUTHORIZATION_SERVER_URL
+	connectionDetails["ZEEBE_CLIENT_ID"] = []byte(inline.ID)
+	connectionDetails["ZEEBE_ADDRESS"] = []byte(inline.ZeebeAddress)
+	connectionDetails["ZEEBE_AUTHORIZATION_SERVER_URL"] = []byte(inline.ZeebeAuthorizationServerURL)
+	cr.GetObservation().ZeebeClientID = inline.ID
+	cr.GetObservation().ZeebeAddress = inline.ZeebeAddress
+	cr.GetObservation().ZeebeAuthorizationServerURL = inline.ZeebeAuthorizationServerURL
 
 	return managed.ExternalObservation{
 		// Return false when the external resource does not exist. This lets
@@ -176,45 +242,75 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}, nil
 }
 
+// observeCluster is called when the client does not exist. It reports the
+// client as not existing, unless its cluster is not healthy yet. In that case
+// the client is reported as existing and a waiting condition is set, so that
+// the client is not created before its cluster is able to serve it. It
+// returns an error once the cluster has not existed for a while.
+func (c *external) observeCluster(ctx context.Context, cr clientResource) (managed.ExternalObservation, error) {
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	clusterID := cr.GetParameters().ClusterID
+	inline, found, err := c.cache.GetCluster(ctx, c.service, clusterID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetCluster)
+	}
+	if !found {
+		// The condition keeps its transition time while the cluster is
+		// missing, which tells how long the Client has been waiting.
+		waiting := v1beta1.WaitingForCluster(fmt.Sprintf(errClusterNotFound, clusterID))
+		if prev := cr.GetCondition(xpv1.TypeReady); prev.Equal(waiting) && time.Since(prev.LastTransitionTime.Time) > clusterMissingTimeout {
+			return managed.ExternalObservation{}, errors.Errorf(errClusterMissing, clusterID, clusterMissingTimeout)
+		}
+		cr.SetConditions(waiting)
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	s := inline.Status.Zeebe
+	if s == "" {
+		s = inline.Status.Ready
+	}
+	if s != camunda.Healthy {
+		cr.SetConditions(v1beta1.WaitingForCluster(fmt.Sprintf(errClusterNotReady, clusterID, s)))
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	return managed.ExternalObservation{ResourceExists: false}, nil
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	log, _ := logr.FromContext(ctx)
-	cr, ok := mg.(*v1alpha1.Client)
+	cr, ok := mg.(clientResource)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotclient)
 	}
 
-	clientName := cr.GetName()
-
-	newClientConfiguration := console.CreateClusterClientBody{
-		ClientName: clientName,
-	}
-
-	ctx = context.WithValue(ctx, console.ContextAccessToken, c.service.AccessToken)
-	inline, _, err := c.service.APIClient.ClustersApi.CreateClient(ctx, cr.Spec.ForProvider.ClusterID).
-		CreateClusterClientBody(newClientConfiguration).
-		Execute()
+	inline, err := c.service.CreateClient(ctx, cr.GetParameters().ClusterID, cr.GetName())
 	if err != nil {
 		log.Error(err, "client-creation")
 		return managed.ExternalCreation{}, err
 	}
+	c.cache.InvalidateClients(cr.GetParameters().ClusterID)
 
-	meta.SetExternalName(cr, inline.ClientId)
+	meta.SetExternalName(cr, inline.ID)
 
-	cr.Status.AtProvider.ZeebeClientID = inline.ClientId
+	cr.GetObservation().ZeebeClientID = inline.ID
 
 	return managed.ExternalCreation{
 		// Optionally return any details that may be required to connect to the
 		// external resource. These will be stored as the connection secret.
 		ConnectionDetails: managed.ConnectionDetails{
-			"ZEEBE_CLIENT_ID":     []byte(inline.ClientId),
-			"ZEEBE_CLIENT_SECRET": []byte(inline.ClientSecret),
+			"ZEEBE_CLIENT_ID":     []byte(inline.ID),
+			"ZEEBE_CLIENT_SECRET": []byte(inline.Secret),
 		},
 	}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
 	log, _ := logr.FromContext(ctx)
-	cr, ok := mg.(*v1alpha1.Client)
+	cr, ok := mg.(clientResource)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotclient)
 	}
@@ -230,21 +326,18 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	log, _ := logr.FromContext(ctx)
-	cr, ok := mg.(*v1alpha1.Client)
+	cr, ok := mg.(clientResource)
 	if !ok {
 		return errors.New(errNotclient)
 	}
 
 	log.Info("Deleting client", "custom-resource", cr)
 
-	ctx = context.WithValue(ctx, console.ContextAccessToken, c.service.AccessToken)
-	resp, err := c.service.ClustersApi.DeleteClient(ctx, cr.Spec.ForProvider.ClusterID, meta.GetExternalName(cr)).
-		Execute()
-	if err != nil {
-		log.Info("the response on client deletion", resp)
-		log.Error(err, "the error on client deletion")
-		return err
+	err := c.service.DeleteClient(ctx, cr.GetParameters().ClusterID, meta.GetExternalName(cr))
+	if err := resource.Ignore(camunda.IsNotFound, err); err != nil {
+		return errors.Wrap(err, errDeleteClient)
 	}
-
+	// The cached list may still hold the client, even if it was already gone.
+	c.cache.InvalidateClients(cr.GetParameters().ClusterID)
 	return nil
 }
@@ -18,12 +18,25 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	console "github.com/sijoma/console-customer-api-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/crossplane/provider-camunda/apis/client/v1beta1"
 	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -37,40 +50,361 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
-func TestObserve(t *testing.T) {
-	type fields struct {
-		service camunda.Service
-	}
+const clusterID = "2611e047-74ab-47ba-aae4-115be2918fbe"
 
-	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+// newExternal returns an external client that talks to the supplied fake
+// Console API.
+func newExternal(t *testing.T, srv *fake.Server) *external {
+	t.Helper()
+	svc, err := camunda.Authenticate(context.Background(), srv.Credentials())
+	if err != nil {
+		t.Fatalf("camunda.Authenticate(...): unexpected error: %v", err)
 	}
+	return &external{service: svc, cache: camunda.NewCache(0)}
+}
 
+func TestObserve(t *testing.T) {
 	type want struct {
-		o   managed.ExternalObservation
+		// The client's external-name is only known once the fake Console API
+		// is running, so the wanted resource is built from it.
+		cr  func(srv *fake.Server, id string) *v1beta1.Client
+		o   func(srv *fake.Server, id string) managed.ExternalObservation
 		err error
 	}
 
+	unchanged := func(cr *v1beta1.Client) func(*fake.Server, string) *v1beta1.Client {
+		return func(_ *fake.Server, _ string) *v1beta1.Client { return cr }
+	}
+	waiting := func(_ *fake.Server, _ string) managed.ExternalObservation {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	}
+	missing := func(_ *fake.Server, _ string) managed.ExternalObservation {
+		return managed.ExternalObservation{ResourceExists: false}
+	}
+	waitingSince := func(d time.Duration) xpv1.Condition {
+		c := v1beta1.WaitingForCluster(fmt.Sprintf(errClusterNotFound, clusterID))
+		c.LastTransitionTime = metav1.NewTime(time.Now().Add(-d))
+		return c
+	}
+	details := func(srv *fake.Server, id string) managed.ExternalObservation {
+		return managed.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+			ConnectionDetails: managed.ConnectionDetails{
+				"ZEEBE_CLIENT_ID":                []byte(id),
+				"ZEEBE_ADDRESS":                  []byte("zeebe:443"),
+				"ZEEBE_AUTHORIZATION_SERVER_URL": []byte(srv.URL + fake.TokenPath),
+			},
+		}
+	}
+	observed := func(srv *fake.Server, id string) v1beta1.ClientObservation {
+		return v1beta1.ClientObservation{
+			ZeebeClientID:               id,
+			ZeebeAddress:                "zeebe:443",
+			ZeebeAuthorizationServerURL: srv.URL + fake.TokenPath,
+		}
+	}
+
 	cases := map[string]struct {
-		reason string
-		fields fields
-		args   args
-		want   want
+		reason    string
+		status    console.ClusterStatus
+		noCluster bool
+		faults    map[string]fake.Fault
+		// external returns the external-name of the observed Client.
+		external func(srv *fake.Server) string
+		// conditions are the conditions of the observed Client.
+		conditions []xpv1.Condition
+		want       want
 	}{
-		// TODO: Add test cases.
+		"Available": {
+			reason:   "An existing client should be reported as available, with its connection details.",
+			external: func(srv *fake.Server) string { return srv.AddClient(clusterID, "cool-client").ClientId },
+			want: want{
+				cr: func(srv *fake.Server, id string) *v1beta1.Client {
+					return &v1beta1.Client{
+						ObjectMeta: metav1.ObjectMeta{Name: "cool-client", Annotations: map[string]string{meta.AnnotationKeyExternalName: id}},
+						Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+						Status: v1beta1.ClientStatus{
+							ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+							AtProvider:     observed(srv, id),
+						},
+					}
+				},
+				o: details,
+			},
+		},
+		"NamedDifferently": {
+			reason:   "An existing client with a different name, e.g. an imported one, should be reported as available.",
+			external: func(srv *fake.Server) string { return srv.AddClient(clusterID, "other-client").ClientId },
+			want: want{
+				cr: func(srv *fake.Server, id string) *v1beta1.Client {
+					return &v1beta1.Client{
+						ObjectMeta: metav1.ObjectMeta{Name: "cool-client", Annotations: map[string]string{meta.AnnotationKeyExternalName: id}},
+						Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+						Status: v1beta1.ClientStatus{
+							ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+							AtProvider:     observed(srv, id),
+						},
+					}
+				},
+				o: details,
+			},
+		},
+		"NotCreated": {
+			reason: "A client that was not created yet should be reported as not existing once its cluster is healthy.",
+			want: want{
+				cr: unchanged(&v1beta1.Client{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+					Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+				}),
+				o: missing,
+			},
+		},
+		"Gone": {
+			reason:   "A client that no longer exists should be reported as not existing.",
+			external: func(_ *fake.Server) string { return "gone" },
+			want: want{
+				cr: func(_ *fake.Server, _ string) *v1beta1.Client {
+					return &v1beta1.Client{
+						ObjectMeta: metav1.ObjectMeta{Name: "cool-client", Annotations: map[string]string{meta.AnnotationKeyExternalName: "gone"}},
+						Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+					}
+				},
+				o: missing,
+			},
+		},
+		"ClusterNotFound": {
+			reason:    "A client should wait for a cluster that does not exist.",
+			noCluster: true,
+			want: want{
+				cr: unchanged(&v1beta1.Client{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+					Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+					Status:     v1beta1.ClientStatus{ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{v1beta1.WaitingForCluster(fmt.Sprintf(errClusterNotFound, clusterID))}}}},
+				}),
+				o: waiting,
+			},
+		},
+		"ClusterStillNotFound": {
+			reason:     "A client should keep waiting for a cluster that has not existed for less than the timeout.",
+			noCluster:  true,
+			conditions: []xpv1.Condition{waitingSince(time.Minute)},
+			want: want{
+				cr: unchanged(&v1beta1.Client{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+					Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+					Status:     v1beta1.ClientStatus{ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{v1beta1.WaitingForCluster(fmt.Sprintf(errClusterNotFound, clusterID))}}}},
+				}),
+				o: waiting,
+			},
+		},
+		"ClusterMissing": {
+			reason:     "An error should be returned once a client waited too long for a cluster that does not exist.",
+			noCluster:  true,
+			conditions: []xpv1.Condition{waitingSince(clusterMissingTimeout + time.Minute)},
+			want: want{
+				cr: unchanged(&v1beta1.Client{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+					Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+					Status:     v1beta1.ClientStatus{ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{v1beta1.WaitingForCluster(fmt.Sprintf(errClusterNotFound, clusterID))}}}},
+				}),
+				o:   func(_ *fake.Server, _ string) managed.ExternalObservation { return managed.ExternalObservation{} },
+				err: errors.Errorf(errClusterMissing, clusterID, clusterMissingTimeout),
+			},
+		},
+		"ClusterCreating": {
+			reason: "A client should wait for a cluster that is still being created.",
+			status: fake.Status(console.CREATING),
+			want: want{
+				cr: unchanged(&v1beta1.Client{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+					Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+					Status:     v1beta1.ClientStatus{ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{v1beta1.WaitingForCluster(fmt.Sprintf(errClusterNotReady, clusterID, camunda.Creating))}}}},
+				}),
+				o: waiting,
+			},
+		},
+		"GetClusterError": {
+			reason: "Errors getting the cluster of a client should be returned.",
+			faults: map[string]fake.Fault{"GET /clusters": {StatusCode: http.StatusInternalServerError}},
+			want: want{
+				cr: unchanged(&v1beta1.Client{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+					Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+				}),
+				o:   func(_ *fake.Server, _ string) managed.ExternalObservation { return managed.ExternalObservation{} },
+				err: errors.Wrap(errors.Wrap(errors.New("500 Internal Server Error"), "cannot list clusters"), errGetCluster),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: &tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			srv := fake.NewServer()
+			defer srv.Close()
+			if !tc.noCluster {
+				zeebe := "zeebe"
+				srv.AddCluster(console.Cluster{Uuid: clusterID, Name: "cool-cluster", Status: tc.status, Links: console.ClusterLinks{Zeebe: &zeebe}})
+			}
+			id := ""
+			if tc.external != nil {
+				id = tc.external(srv)
+			}
+			cr := &v1beta1.Client{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+				Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+			}
+			if id != "" {
+				meta.SetExternalName(cr, id)
+			}
+			cr.SetConditions(tc.conditions...)
+			for endpoint, f := range tc.faults {
+				srv.Inject(endpoint, f)
+			}
+
+			e := newExternal(t, srv)
+			got, err := e.Observe(logr.NewContext(context.Background(), logr.Discard()), cr)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+			if diff := cmp.Diff(tc.want.o(srv, id), got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.cr(srv, id), cr, test.EquateConditions(), cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	srv := fake.NewServer(fake.WithClusters(console.Cluster{Uuid: clusterID, Name: "cool-cluster"}))
+	defer srv.Close()
+	e := newExternal(t, srv)
+
+	cr := &v1beta1.Client{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+		Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+	}
+	got, err := e.Create(logr.NewContext(context.Background(), logr.Discard()), cr)
+	if err != nil {
+		t.Fatalf("\nCreating a Client should not fail.\ne.Create(...): unexpected error: %v", err)
+	}
+
+	clients := srv.Clients(clusterID)
+	if len(clients) != 1 {
+		t.Fatalf("\nCreating a Client should create a client of its cluster.\ne.Create(...): got %d clients", len(clients))
+	}
+	want := managed.ExternalCreation{ConnectionDetails: managed.ConnectionDetails{
+		"ZEEBE_CLIENT_ID":     []byte(clients[0].ClientId),
+		"ZEEBE_CLIENT_SECRET": []byte(clients[0].ClientSecret),
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe secret of a client is only returned on creation.\ne.Create(...): -want, +got:\n%s\n", diff)
+	}
+	created := &v1beta1.Client{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-client", Annotations: map[string]string{meta.AnnotationKeyExternalName: clients[0].ClientId}},
+		Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+		Status:     v1beta1.ClientStatus{AtProvider: v1beta1.ClientObservation{ZeebeClientID: clients[0].ClientId}},
+	}
+	if diff := cmp.Diff(created, cr); diff != "" {
+		t.Errorf("\nThe client ID should become the external-name.\ne.Create(...): -want managed resource, +got managed resource:\n%s\n", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type want struct {
+		err     error
+		clients int
+	}
+
+	cases := map[string]struct {
+		reason string
+		faults map[string]fake.Fault
+		// external returns the external-name of the deleted Client.
+		external func(srv *fake.Server) string
+		want     want
+	}{
+		"Deleted": {
+			reason:   "Deleting a Client should delete its client.",
+			external: func(srv *fake.Server) string { return srv.AddClient(clusterID, "cool-client").ClientId },
+		},
+		"AlreadyGone": {
+			reason:   "Deleting a Client whose client is already gone should not fail.",
+			external: func(_ *fake.Server) string { return "gone" },
+		},
+		"DeleteError": {
+			reason:   "Errors deleting the client should be returned.",
+			faults:   map[string]fake.Fault{"DELETE /clusters/{id}/clients/{id}": {StatusCode: http.StatusInternalServerError}},
+			external: func(srv *fake.Server) string { return srv.AddClient(clusterID, "cool-client").ClientId },
+			want: want{
+				err:     errors.Wrap(errors.New("500 Internal Server Error"), errDeleteClient),
+				clients: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := fake.NewServer(fake.WithClusters(console.Cluster{Uuid: clusterID, Name: "cool-cluster"}))
+			defer srv.Close()
+			e := newExternal(t, srv)
+			for endpoint, f := range tc.faults {
+				srv.Inject(endpoint, f)
+			}
+
+			cr := &v1beta1.Client{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-client", Annotations: map[string]string{meta.AnnotationKeyExternalName: tc.external(srv)}},
+				Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+			}
+			err := e.Delete(logr.NewContext(context.Background(), logr.Discard()), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.clients, len(srv.Clients(clusterID))); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want clients, +got clients:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	pi := poll.Intervals{Busy: 10 * time.Second, Idle: 10 * time.Minute}
+
+	withConditions := func(c ...xpv1.Condition) *v1beta1.Client {
+		cr := &v1beta1.Client{}
+		cr.Status.SetConditions(c...)
+		return cr
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   time.Duration
+	}{
+		"WaitingForCluster": {
+			reason: "A client that waits for its cluster should be polled at the busy interval.",
+			mg:     withConditions(v1beta1.WaitingForCluster("")),
+			want:   pi.Busy,
+		},
+		"Available": {
+			reason: "An available client should be polled at the idle interval.",
+			mg:     withConditions(xpv1.Available()),
+			want:   pi.Idle,
+		},
+		"Unavailable": {
+			reason: "An unavailable client should be polled at the default interval.",
+			mg:     withConditions(xpv1.Unavailable()),
+			want:   time.Minute,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := pollInterval(pi)(tc.mg, time.Minute)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npollInterval(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -18,13 +18,14 @@ package cluster
 
 import (
 	"context"
+	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	console "github.com/sijoma/console-customer-api-go"
-	"k8s.io/apimachinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -37,9 +38,16 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
-	"github.com/crossplane/provider-camunda/apis/cluster/v1alpha1"
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
 	apisv1alpha1 "github.com/crossplane/provider-camunda/apis/v1alpha1"
+	apisv1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/controller/config"
 	"github.com/crossplane/provider-camunda/internal/controller/features"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+	"github.com/crossplane/provider-camunda/internal/metrics"
+	"github.com/crossplane/provider-camunda/internal/tracing"
 )
 
 const (
@@ -49,11 +57,69 @@ const (
 	errGetCreds     = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
+
+	errGetCluster        = "cannot get cluster"
+	errCreateCluster     = "cannot create cluster"
+	errDeleteCluster     = "cannot delete cluster"
+	errListClients       = "cannot list dependent clients"
+	errDeleteClient      = "cannot delete dependent client"
+	errDependentClients  = "cannot delete cluster while %d clients reference it"
+	errIndexClients      = "cannot index clients by cluster ID"
+	errDeletionProtected = "cannot delete cluster: deletion protection is enabled, remove spec.forProvider.deletionProtection and the " + v1beta1.AnnotationKeyDeletionProtection + " annotation first"
+	errMissingParameters = "channel, generation, region and planType are required to create a cluster"
 )
 
-// Setup adds a controller that reconciles MyType managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.ClusterGroupKind)
+// Event reasons.
+const (
+	reasonDeletionProtected event.Reason = "DeletionProtected"
+)
+
+// clusterIDField indexes Clients by the ID of the cluster they belong to.
+const clusterIDField = "spec.forProvider.clusterID"
+
+// A clusterResource is a Cluster managed resource of either scope.
+type clusterResource interface {
+	resource.Managed
+	GetParameters() *v1beta1.ClusterParameters
+	GetObservation() *v1beta1.ClusterObservation
+	DeletionProtected() bool
+}
+
+// A clientResource is a Client managed resource of either scope.
+type clientResource interface {
+	client.Object
+	GetParameters() *clientv1beta1.ClientParameters
+}
+
+// Setup adds controllers that reconcile cluster scoped and namespaced Cluster
+// managed resources. Clusters are polled at the supplied intervals depending
+// on their state.
+func Setup(mgr ctrl.Manager, o controller.Options, pi poll.Intervals) error {
+	// Clusters of either scope may be referenced by Clients of either scope.
+	for _, obj := range []client.Object{&clientv1beta1.Client{}, &namespacedv1beta1.Client{}} {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), obj, clusterIDField, func(o client.Object) []string {
+			cl, ok := o.(clientResource)
+			if !ok || cl.GetParameters().ClusterID == "" {
+				return nil
+			}
+			return []string{cl.GetParameters().ClusterID}
+		}); err != nil {
+			return errors.Wrap(err, errIndexClients)
+		}
+	}
+
+	if err := setup(mgr, o, pi, v1beta1.ClusterGroupVersionKind, func() resource.Managed { return &v1beta1.Cluster{} },
+		resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{})); err != nil {
+		return err
+	}
+
+	return setup(mgr, o, pi, namespacedv1beta1.ClusterGroupVersionKind, func() resource.Managed { return &namespacedv1beta1.Cluster{} },
+		config.NewNamespacedUsageTracker(mgr.GetClient()))
+}
+
+// setup adds a controller that reconciles the supplied kind of Cluster.
+func setup(mgr ctrl.Manager, o controller.Options, pi poll.Intervals, gvk schema.GroupVersionKind, newCluster func() resource.Managed, usage resource.Tracker) error {
+	name := managed.ControllerName(gvk.GroupKind().String())
 
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
 	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
@@ -61,11 +127,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	}
 
 	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.ClusterGroupVersionKind),
-		managed.WithExternalConnecter(&connector{
+		resource.ManagedKind(gvk),
+		managed.WithExternalConnecter(tracing.NewConnecter(gvk.Kind, &connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: camunda.NewService}),
+			usage:        usage,
+			record:       event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+			newServiceFn: camunda.NewService,
+			// Observations must be fresh enough for busy resources.
+			cacheTTL: pi.Busy})),
+		// The external-name of a cluster is the UUID assigned by the Console
+		// API on creation, so it must not default to the resource's name.
+		managed.WithInitializers(),
+		managed.WithPollInterval(o.PollInterval),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithConnectionPublishers(cps...))
@@ -73,8 +146,29 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		For(&v1alpha1.Cluster{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		For(newCluster()).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, mgr.GetClient(), newCluster, pollInterval(pi)), o.GlobalRateLimiter))
+}
+
+// pollInterval polls clusters that are being created, updated or deleted at
+// the busy interval and healthy clusters at the idle interval.
+func pollInterval(pi poll.Intervals) poll.IntervalHook {
+	return func(mg resource.Managed, pollInterval time.Duration) time.Duration {
+		cr, ok := mg.(clusterResource)
+		if !ok {
+			return pollInterval
+		}
+		if meta.WasDeleted(cr) {
+			return pi.Busy
+		}
+		switch camunda.HealthStatus(cr.GetObservation().Health.Ready) {
+		case "", camunda.Creating, camunda.Updating:
+			return pi.Busy
+		case camunda.Healthy:
+			return pi.Idle
+		}
+		return pollInterval
+	}
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
@@ -82,7 +176,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(ctx context.Context, creds []byte) (*camunda.Service, error)
+	record       event.Recorder
+	newServiceFn func(ctx context.Context, key string, creds []byte) (camunda.API, error)
+	cacheTTL     time.Duration
 }
 
 // Connect typically produces an ExternalClient by:
@@ -91,8 +187,7 @@ type connector struct {
 // 3. Getting the credentials specified by the ProviderConfig.
 // 4. Using the credentials to form a client.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	cr, ok := mg.(*v1alpha1.Cluster)
-	if !ok {
+	if _, ok := mg.(clusterResource); !ok {
 		return nil, errors.New(errNotMyType)
 	}
 
@@ -100,77 +195,111 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+	pc, err := config.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	data, err := pc.Credentials(ctx, c.kube)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(ctx, data)
+	svc, err := c.newServiceFn(ctx, pc.Key, data)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: svc}, nil
+	return &external{
+		service:  svc,
+		cache:    camunda.ObservationCache(pc.Key, data, c.cacheTTL),
+		kube:     c.kube,
+		record:   c.record,
+		defaults: pc.Spec.ClusterDefaults,
+	}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	service *camunda.Service
+	service  camunda.ClusterAPI
+	cache    *camunda.Cache
+	kube     client.Client
+	record   event.Recorder
+	defaults *apisv1beta1.ClusterDefaults
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	log, _ := logr.FromContext(ctx)
-	cr, ok := mg.(*v1alpha1.Cluster)
+	cr, ok := mg.(clusterResource)
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotMyType)
 	}
 
-	clusterName := cr.GetName()
+	clearDeletionProtected(cr)
+
+	metricsName := metricsName(cr)
+	clusterID := meta.GetExternalName(cr)
+	if clusterID == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 
-	ctx = context.WithValue(ctx, console.ContextAccessToken, c.service.AccessToken)
-	inline, _, err := c.service.APIClient.ClustersApi.GetCluster(ctx, meta.GetExternalName(cr)).Execute()
+	inline, found, err := c.cache.GetCluster(ctx, c.service, clusterID)
 	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetCluster)
+	}
+	if !found {
+		metrics.DeleteCluster(metricsName)
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	log.Info("observed-cluster", "cluster-status", inline.Status, "cluster-endpoints", inline.Endpoints)
+
+	if meta.WasDeleted(cr) {
+		// The cluster is still being torn down. Keep reporting it as existing
+		// so that the finalizer is only removed once it is gone.
+		cr.SetConditions(xpv1.Deleting())
 		return managed.ExternalObservation{
-			ResourceExists:   false,
-			ResourceUpToDate: false,
+			ResourceExists:   true,
+			ResourceUpToDate: true,
 		}, nil
 	}
 
-	log.Info("observed-cluster", "cluster-status", inline.Status, "cluster-links", inline.Links)
+	lateInitialized := lateInitialize(cr.GetParameters(), inline)
 
-	if inline.Status.ZeebeStatus != nil {
-		switch *inline.Status.ZeebeStatus {
-		case console.HEALTHY:
-			cr.Status.SetConditions(xpv1.Available())
-		case console.CREATING:
-			cr.Status.SetConditions(xpv1.Creating())
-		case console.UNHEALTHY, console.UPDATING:
-			cr.Status.SetConditions(xpv1.Unavailable())
-		default:
-			cr.Status.SetConditions(xpv1.Unavailable())
-		}
+	cr.GetObservation().Health = health(inline.Status)
+	recordComponentHealth(metricsName, inline.Status)
 
+	switch inline.Status.Zeebe {
+	case "":
+	case camunda.Healthy:
+		cr.SetConditions(xpv1.Available())
+	case camunda.Creating:
+		cr.SetConditions(xpv1.Creating())
+	default:
+		cr.SetConditions(xpv1.Unavailable())
 	}
 
+	// Each endpoint is published once it is known. The components of a
+	// cluster may get their endpoints at different times, and a plan may not
+	// include all of them.
+	e := inline.Endpoints
+	cr.GetObservation().Endpoints = v1beta1.ClusterEndpoints{
+		Zeebe:    e.Zeebe,
+		Operate:  e.Operate,
+		Tasklist: e.Tasklist,
+		Optimize: e.Optimize,
+	}
 	connectionDetails := managed.ConnectionDetails{}
-	// TODO: Proper check of all nil-pointers
-	if inline.Links.Operate != nil {
-		connectionDetails["operate"] = []byte(inline.Links.GetOperate())
-		connectionDetails["optimize"] = []byte(inline.Links.GetOptimize())
-		connectionDetails["tasklist"] = []byte(inline.Links.GetTasklist())
-		connectionDetails["zeebe"] = []byte(inline.Links.GetZeebe())
-
-		cr.Status.AtProvider.Operate = inline.Links.GetOperate()
-		cr.Status.AtProvider.Optimize = inline.Links.GetOptimize()
-		cr.Status.AtProvider.Tasklist = inline.Links.GetTasklist()
-		cr.Status.AtProvider.Zeebe = inline.Links.GetZeebe()
+	for k, v := range map[string]string{
+		"zeebe":    e.Zeebe,
+		"operate":  e.Operate,
+		"tasklist": e.Tasklist,
+		"optimize": e.Optimize,
+	} {
+		if v != "" {
+			connectionDetails[k] = []byte(v)
+		}
 	}
 
 	return managed.ExternalObservation{
@@ -179,10 +308,16 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		// (re)create the resource, or that it has successfully been deleted.
 		ResourceExists: true,
 
-		// Return false when the external resource exists, but it not up to date
-		// with the desired managed resource state. This lets the managed
-		// resource reconciler know that it needs to call Update.
-		ResourceUpToDate: inline.Name == clusterName,
+		// The Console API cannot change a cluster once it is created, not even
+		// its name, so there is nothing Update could bring up to date. The
+		// cluster is identified by its external-name, so a Cluster may be
+		// named differently, e.g. because it imports a cluster whose name is
+		// not a valid object name.
+		ResourceUpToDate: true,
+
+		// Return true when the managed resource's spec was filled from the
+		// observed cluster, so that the reconciler persists it.
+		ResourceLateInitialized: lateInitialized,
 
 		// Return any details that may be required to connect to the external
 		// resource. These will be stored as the connection secret.
@@ -192,29 +327,35 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	log, _ := logr.FromContext(ctx)
-	cr, ok := mg.(*v1alpha1.Cluster)
+	cr, ok := mg.(clusterResource)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotMyType)
 	}
 
-	newClusterConfiguration := console.CreateClusterBody{
-		Name:         cr.GetName(),
-		PlanTypeId:   cr.Spec.ForProvider.PlanType,
-		ChannelId:    cr.Spec.ForProvider.Channel,
-		GenerationId: cr.Spec.ForProvider.Generation,
-		RegionId:     cr.Spec.ForProvider.Region,
+	// Defaults are only applied when a cluster is created, so that they never
+	// override the parameters of an imported cluster. They end up in the spec
+	// once the new cluster is late-initialized.
+	cr.GetParameters().ApplyDefaults(c.defaults)
+
+	p := cr.GetParameters()
+	if p.Channel == "" || p.Generation == "" || p.Region == "" || p.PlanType == "" {
+		return managed.ExternalCreation{}, errors.New(errMissingParameters)
 	}
-	ctx = context.WithValue(ctx, console.ContextAccessToken, c.service.AccessToken)
-	inline, _, err := c.service.APIClient.ClustersApi.CreateCluster(ctx).
-		CreateClusterBody(newClusterConfiguration).
-		Execute()
 
+	id, err := c.service.CreateCluster(ctx, camunda.ClusterSpec{
+		Name:       cr.GetName(),
+		Channel:    p.Channel,
+		Generation: p.Generation,
+		Region:     p.Region,
+		PlanType:   p.PlanType,
+	})
 	if err != nil {
 		log.Error(err, "cluster creation failed")
-		return managed.ExternalCreation{}, err
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateCluster)
 	}
+	c.cache.InvalidateClusters()
 
-	meta.SetExternalName(cr, inline.GetClusterId())
+	meta.SetExternalName(cr, id)
 
 	return managed.ExternalCreation{
 		// Optionally return any details that may be required to connect to the
@@ -223,15 +364,13 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
-func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	log, _ := logr.FromContext(ctx)
-	cr, ok := mg.(*v1alpha1.Cluster)
-	if !ok {
+// Update is never called, since Observe always reports a cluster as up to
+// date.
+func (c *external) Update(_ context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if _, ok := mg.(clusterResource); !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotMyType)
 	}
 
-	log.Info("Updating cluster", "custom-resource", cr)
-
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
 		// external resource. These will be stored as the connection secret.
@@ -241,20 +380,134 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	log, _ := logr.FromContext(ctx)
-	cr, ok := mg.(*v1alpha1.Cluster)
+	cr, ok := mg.(clusterResource)
 	if !ok {
 		return errors.New(errNotMyType)
 	}
 
+	if cr.DeletionProtected() {
+		err := errors.New(errDeletionProtected)
+		c.record.Event(cr, event.Warning(reasonDeletionProtected, err))
+		cr.SetConditions(v1beta1.ProtectedFromDeletion(errDeletionProtected))
+		return err
+	}
+	clearDeletionProtected(cr)
+
+	if err := c.deleteDependentClients(ctx, cr); err != nil {
+		return err
+	}
+
 	log.Info("Deleting cluster", "custom-resource", cr)
 
-	ctx = context.WithValue(ctx, console.ContextAccessToken, c.service.AccessToken)
-	resp, err := c.service.APIClient.ClustersApi.DeleteCluster(ctx, meta.GetExternalName(cr)).Execute()
+	err := c.service.DeleteCluster(ctx, meta.GetExternalName(cr))
+	if err != nil && !camunda.IsNotFound(err) {
+		return errors.Wrap(err, errDeleteCluster)
+	}
+	// The cached list may still hold the cluster, even if it was already
+	// gone.
+	c.cache.InvalidateClusters()
+	return nil
+}
+
+// clearDeletionProtected sets the DeletionProtected condition to false once a
+// Cluster whose deletion was blocked is no longer protected. Clusters whose
+// deletion was never blocked don't get the condition.
+func clearDeletionProtected(cr clusterResource) {
+	if cr.DeletionProtected() || cr.GetCondition(v1beta1.TypeDeletionProtected).Status != corev1.ConditionTrue {
+		return
+	}
+	cr.SetConditions(v1beta1.UnprotectedFromDeletion())
+}
 
-	if err != nil {
-		log.Info("cluster delete response", "response", resp)
-		log.Error(err, "cluster delete error")
+// deleteDependentClients returns an error while Clients still reference the
+// supplied Cluster. Depending on the Cluster's dependent clients policy these
+// Clients are deleted first. Only Clients of the Cluster's scope, and of its
+// namespace if it is namespaced, depend on it; Clients of other tenants that
+// happen to reference the same cluster are left alone.
+func (c *external) deleteDependentClients(ctx context.Context, cr clusterResource) error {
+	var l resource.ManagedList = &clientv1beta1.ClientList{}
+	opts := []client.ListOption{client.MatchingFields{clusterIDField: meta.GetExternalName(cr)}}
+	if ns := cr.GetNamespace(); ns != "" {
+		l = &namespacedv1beta1.ClientList{}
+		opts = append(opts, client.InNamespace(ns))
+	}
+	if err := c.kube.List(ctx, l, opts...); err != nil {
+		return errors.Wrap(err, errListClients)
+	}
+	dependents := l.GetItems()
+	if len(dependents) == 0 {
+		return nil
 	}
 
-	return nil
+	if cr.GetParameters().DependentClientsPolicy == v1beta1.DependentClientsCascade {
+		for _, cl := range dependents {
+			if meta.WasDeleted(cl) {
+				continue
+			}
+			if err := c.kube.Delete(ctx, cl); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, errDeleteClient)
+			}
+		}
+	}
+
+	return errors.Errorf(errDependentClients, len(dependents))
+}
+
+// health returns the health of a cluster and those of its components that
+// report one.
+func health(s camunda.ClusterStatus) v1beta1.ClusterHealth {
+	return v1beta1.ClusterHealth{
+		Ready:    string(s.Ready),
+		Zeebe:    string(s.Zeebe),
+		Operate:  string(s.Operate),
+		Tasklist: string(s.Tasklist),
+		Optimize: string(s.Optimize),
+	}
+}
+
+// metricsName returns the name a Cluster is recorded as in metrics. The names
+// of namespaced Clusters are prefixed by their namespace.
+func metricsName(cr resource.Managed) string {
+	if ns := cr.GetNamespace(); ns != "" {
+		return ns + "/" + cr.GetName()
+	}
+	return cr.GetName()
+}
+
+// recordComponentHealth records the health of each component of a cluster
+// that reports one.
+func recordComponentHealth(cluster string, s camunda.ClusterStatus) {
+	for component, status := range map[string]camunda.HealthStatus{
+		"zeebe":    s.Zeebe,
+		"operate":  s.Operate,
+		"tasklist": s.Tasklist,
+		"optimize": s.Optimize,
+	} {
+		if status == "" {
+			metrics.ClusterComponentHealthy.DeleteLabelValues(cluster, component)
+			continue
+		}
+		metrics.SetClusterComponentHealthy(cluster, component, status == camunda.Healthy)
+	}
+}
+
+// lateInitialize fills the empty parameters of a Cluster from the observed
+// cluster. It returns true if any parameter was changed.
+func lateInitialize(p *v1beta1.ClusterParameters, c *camunda.Cluster) bool {
+	li := false
+	for _, f := range []struct {
+		param    *string
+		observed string
+	}{
+		{param: &p.Channel, observed: c.Channel},
+		{param: &p.Generation, observed: c.Generation},
+		{param: &p.Region, observed: c.Region},
+		{param: &p.PlanType, observed: c.PlanType},
+	} {
+		if *f.param == "" && f.observed != "" {
+			*f.param = f.observed
+			li = true
+		}
+	}
+	return li
 }
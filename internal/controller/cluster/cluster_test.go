@@ -18,12 +18,33 @@ package cluster
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	console "github.com/sijoma/console-customer-api-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	apisv1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
 	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+	"github.com/crossplane/provider-camunda/internal/metrics"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -37,40 +58,983 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
-func TestObserve(t *testing.T) {
-	type fields struct {
-		service camunda.Service
+const clusterID = "2611e047-74ab-47ba-aae4-115be2918fbe"
+
+func str(s string) *string { return &s }
+
+// existing is a cluster the fake Console API starts with.
+var existing = console.Cluster{
+	Uuid:       clusterID,
+	Name:       "cool-cluster",
+	Channel:    console.ClusterChannel{Uuid: fake.ChannelID},
+	Generation: console.ClusterGeneration{Uuid: fake.GenerationID},
+	Region:     console.ClusterRegion{Uuid: fake.RegionID},
+	PlanType:   console.ClusterPlanType{Uuid: fake.PlanTypeID},
+	Links: console.ClusterLinks{
+		Zeebe:    str("zeebe"),
+		Operate:  str("operate"),
+		Tasklist: str("tasklist"),
+		Optimize: str("optimize"),
+	},
+}
+
+// newExternal returns an external client that talks to the supplied fake
+// Console API.
+func newExternal(t *testing.T, srv *fake.Server, kube client.Client) *external {
+	t.Helper()
+	svc, err := camunda.Authenticate(context.Background(), srv.Credentials())
+	if err != nil {
+		t.Fatalf("camunda.Authenticate(...): unexpected error: %v", err)
 	}
+	return &external{service: svc, cache: camunda.NewCache(0), kube: kube, record: event.NewNopRecorder()}
+}
 
-	type args struct {
-		ctx context.Context
-		mg  resource.Managed
+func TestConnect(t *testing.T) {
+	acme := fake.NewServer(fake.WithOrganization("acme"), fake.WithClusters(console.Cluster{Uuid: "acme-cluster", Name: "acme"}))
+	defer acme.Close()
+	initech := fake.NewServer(fake.WithOrganization("initech"), fake.WithClusters(console.Cluster{Uuid: "initech-cluster", Name: "initech"}))
+	defer initech.Close()
+
+	// The cluster scoped ProviderConfig "example" uses the credentials of
+	// acme, and the namespaced ProviderConfig "example" of the tenant
+	// namespace those of initech.
+	secretRef := func(ns string) xpv1.CommonCredentialSelectors {
+		return xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: ns, Name: "credentials"},
+			Key:             "credentials",
+		}}
+	}
+	spec := func(ns string) apisv1beta1.ProviderConfigSpec {
+		return apisv1beta1.ProviderConfigSpec{Credentials: apisv1beta1.ProviderCredentials{
+			Source:                    xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: secretRef(ns),
+		}}
+	}
+	kube := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *apisv1beta1.ProviderConfig:
+			o.Spec = spec("crossplane-system")
+		case *namespacedv1beta1.ProviderConfig:
+			o.Spec = spec("tenant")
+		case *corev1.Secret:
+			creds := map[string][]byte{"crossplane-system": acme.Credentials(), "tenant": initech.Credentials()}
+			o.Data = map[string][]byte{"credentials": creds[key.Namespace]}
+		}
+		return nil
+	}}
+	c := &connector{
+		kube:         kube,
+		usage:        resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+		newServiceFn: camunda.NewService,
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   []string
+	}{
+		"ClusterScoped": {
+			reason: "A Cluster should work in the organization of its ProviderConfig.",
+			mg:     &v1beta1.Cluster{},
+			want:   []string{"acme-cluster"},
+		},
+		"Namespaced": {
+			reason: "A namespaced Cluster should work in the organization of its namespace's ProviderConfig, not in that of another ProviderConfig.",
+			mg:     &namespacedv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"}},
+			want:   []string{"initech-cluster"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.mg.SetProviderConfigReference(&xpv1.Reference{Name: "example"})
+			e, err := c.Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\nc.Connect(...): unexpected error: %v", tc.reason, err)
+			}
+			l, err := e.(*external).service.ListClusters(context.Background())
+			if err != nil {
+				t.Fatalf("\n%s\nListClusters(...): unexpected error: %v", tc.reason, err)
+			}
+			got := make([]string, len(l))
+			for i := range l {
+				got[i] = l[i].ID
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want clusters, +got clusters:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	healthy := string(camunda.Healthy)
+	deleted := metav1.Now()
+	params := v1beta1.ClusterParameters{
+		Channel:    fake.ChannelID,
+		Generation: fake.GenerationID,
+		Region:     fake.RegionID,
+		PlanType:   fake.PlanTypeID,
+	}
+	observed := v1beta1.ClusterObservation{
+		Health:    v1beta1.ClusterHealth{Ready: healthy, Zeebe: healthy, Operate: healthy, Tasklist: healthy, Optimize: healthy},
+		Endpoints: v1beta1.ClusterEndpoints{Zeebe: "zeebe", Operate: "operate", Tasklist: "tasklist", Optimize: "optimize"},
+	}
+	details := managed.ConnectionDetails{
+		"zeebe":    []byte("zeebe"),
+		"operate":  []byte("operate"),
+		"tasklist": []byte("tasklist"),
+		"optimize": []byte("optimize"),
 	}
 
 	type want struct {
+		cr  *v1beta1.Cluster
 		o   managed.ExternalObservation
 		err error
 	}
 
 	cases := map[string]struct {
 		reason string
-		fields fields
-		args   args
+		faults map[string]fake.Fault
+		links  *console.ClusterLinks
+		cr     *v1beta1.Cluster
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NoExternalName": {
+			reason: "A Cluster without an external-name should not exist yet.",
+			cr:     &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"}},
+			want: want{
+				cr: &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"}},
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "A Cluster whose cluster does not exist should be reported as not existing.",
+			cr:     &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: "gone"}}},
+			want: want{
+				cr: &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: "gone"}}},
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"GetError": {
+			reason: "Errors getting the cluster should be returned.",
+			faults: map[string]fake.Fault{"GET /clusters": {StatusCode: http.StatusInternalServerError}},
+			cr:     &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}},
+			want: want{
+				cr:  &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}},
+				err: errors.Wrap(errors.Wrap(errors.New("500 Internal Server Error"), "cannot list clusters"), errGetCluster),
+			},
+		},
+		"GetClusterError": {
+			reason: "Errors other than not found confirming a cluster that is not listed should be returned.",
+			faults: map[string]fake.Fault{"GET /clusters/{id}": {StatusCode: http.StatusInternalServerError}},
+			cr:     &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: "unlisted"}}},
+			want: want{
+				cr:  &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: "unlisted"}}},
+				err: errors.Wrap(errors.Wrap(errors.New("500 Internal Server Error"), "cannot get cluster"), errGetCluster),
+			},
+		},
+		"Deleting": {
+			reason: "A deleted Cluster whose cluster still exists should be reported as existing and deleting.",
+			cr: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}, DeletionTimestamp: &deleted},
+				Spec:       v1beta1.ClusterSpec{ForProvider: params},
+			},
+			want: want{
+				cr: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}, DeletionTimestamp: &deleted},
+					Spec:       v1beta1.ClusterSpec{ForProvider: params},
+					Status:     v1beta1.ClusterStatus{ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Deleting()}}}},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Healthy": {
+			reason: "A healthy cluster should be reported as existing and available, with its endpoints.",
+			cr: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}},
+				Spec:       v1beta1.ClusterSpec{ForProvider: params},
+			},
+			want: want{
+				cr: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}},
+					Spec:       v1beta1.ClusterSpec{ForProvider: params},
+					Status: v1beta1.ClusterStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider:     observed,
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: details},
+			},
+		},
+		"LateInitialized": {
+			reason: "The empty parameters of an imported cluster should be late-initialized.",
+			cr:     &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}},
+			want: want{
+				cr: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}},
+					Spec:       v1beta1.ClusterSpec{ForProvider: params},
+					Status: v1beta1.ClusterStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider:     observed,
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true, ConnectionDetails: details},
+			},
+		},
+		"PartialEndpoints": {
+			reason: "Only the endpoints a cluster already has should be observed and published.",
+			links:  &console.ClusterLinks{Zeebe: str("zeebe"), Optimize: str("")},
+			cr: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}},
+				Spec:       v1beta1.ClusterSpec{ForProvider: params},
+			},
+			want: want{
+				cr: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}},
+					Spec:       v1beta1.ClusterSpec{ForProvider: params},
+					Status: v1beta1.ClusterStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider: v1beta1.ClusterObservation{
+							Health:    observed.Health,
+							Endpoints: v1beta1.ClusterEndpoints{Zeebe: "zeebe"},
+						},
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{"zeebe": []byte("zeebe")}},
+			},
+		},
+		"NamedDifferently": {
+			reason: "A cluster whose name differs from the Cluster's should be reported as up to date, since clusters cannot be renamed.",
+			cr: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "renamed-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}},
+				Spec:       v1beta1.ClusterSpec{ForProvider: params},
+			},
+			want: want{
+				cr: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "renamed-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}},
+					Spec:       v1beta1.ClusterSpec{ForProvider: params},
+					Status: v1beta1.ClusterStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider:     observed,
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: details},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{service: &tc.fields.service}
-			got, err := e.Observe(tc.args.ctx, tc.args.mg)
+			cl := existing
+			if tc.links != nil {
+				cl.Links = *tc.links
+			}
+			srv := fake.NewServer(fake.WithClusters(cl))
+			defer srv.Close()
+			e := newExternal(t, srv, nil)
+			for endpoint, f := range tc.faults {
+				srv.Inject(endpoint, f)
+			}
+
+			got, err := e.Observe(logr.NewContext(context.Background(), logr.Discard()), tc.cr)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions(), cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveConditions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		zeebe  camunda.HealthStatus
+		want   []xpv1.Condition
+	}{
+		"Healthy": {
+			reason: "A cluster with a healthy Zeebe should be available.",
+			zeebe:  camunda.Healthy,
+			want:   []xpv1.Condition{xpv1.Available()},
+		},
+		"Creating": {
+			reason: "A cluster whose Zeebe is being created should be creating.",
+			zeebe:  camunda.Creating,
+			want:   []xpv1.Condition{xpv1.Creating()},
+		},
+		"Updating": {
+			reason: "A cluster whose Zeebe is being updated should be unavailable.",
+			zeebe:  camunda.Updating,
+			want:   []xpv1.Condition{xpv1.Unavailable()},
+		},
+		"Unreported": {
+			reason: "A cluster whose Zeebe does not report its health should not get a condition.",
+			want:   nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := &fake.MockAPI{
+				MockListClusters: func(_ context.Context) ([]camunda.Cluster, error) {
+					return []camunda.Cluster{{ID: clusterID, Status: camunda.ClusterStatus{Ready: tc.zeebe, Zeebe: tc.zeebe}}}, nil
+				},
+			}
+			e := &external{service: api, cache: camunda.NewCache(0)}
+			cr := &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}}
+
+			if _, err := e.Observe(logr.NewContext(context.Background(), logr.Discard()), cr); err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, cr.Status.Conditions, test.EquateConditions(), cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want conditions, +got conditions:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	params := v1beta1.ClusterParameters{
+		Channel:    fake.ChannelID,
+		Generation: fake.GenerationID,
+		Region:     fake.RegionID,
+		PlanType:   fake.PlanTypeID,
+	}
+
+	type want struct {
+		clusters int
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		faults   map[string]fake.Fault
+		defaults *apisv1beta1.ClusterDefaults
+		cr       *v1beta1.Cluster
+		want     want
+	}{
+		"MissingParameters": {
+			reason: "A Cluster without all parameters should not be created.",
+			cr: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Channel: fake.ChannelID}},
+			},
+			want: want{err: errors.New(errMissingParameters)},
+		},
+		"Defaulted": {
+			reason: "A Cluster should be created with the defaults of its ProviderConfig.",
+			defaults: &apisv1beta1.ClusterDefaults{
+				Channel:    fake.ChannelID,
+				Generation: fake.GenerationID,
+				Region:     fake.RegionID,
+				PlanType:   fake.PlanTypeID,
+			},
+			cr:   &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"}},
+			want: want{clusters: 1},
+		},
+		"Created": {
+			reason: "A Cluster should be created and get the ID of the new cluster as its external-name.",
+			cr: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: params},
+			},
+			want: want{clusters: 1},
+		},
+		"CreateError": {
+			reason: "Errors creating the cluster should be returned.",
+			faults: map[string]fake.Fault{"POST /clusters": {StatusCode: http.StatusTooManyRequests}},
+			cr: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: params},
+			},
+			want: want{err: errors.Wrap(errors.New("429 Too Many Requests"), errCreateCluster)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := fake.NewServer()
+			defer srv.Close()
+			e := newExternal(t, srv, nil)
+			e.defaults = tc.defaults
+			for endpoint, f := range tc.faults {
+				srv.Inject(endpoint, f)
+			}
+
+			_, err := e.Create(logr.NewContext(context.Background(), logr.Discard()), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.clusters == 0 {
+				return
+			}
+			c, ok := srv.Cluster(meta.GetExternalName(tc.cr))
+			if !ok {
+				t.Fatalf("\n%s\ne.Create(...): the external-name %q is not the ID of a cluster", tc.reason, meta.GetExternalName(tc.cr))
+			}
+			if diff := cmp.Diff(tc.cr.GetName(), c.Name); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want cluster name, +got cluster name:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	srv := fake.NewServer(fake.WithClusters(existing))
+	defer srv.Close()
+	e := newExternal(t, srv, nil)
+
+	cr := &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}}
+	got, err := e.Update(logr.NewContext(context.Background(), logr.Discard()), cr)
+	if err != nil {
+		t.Errorf("\nUpdating a Cluster should not fail.\ne.Update(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}}, got); diff != "" {
+		t.Errorf("\nClusters cannot be updated, so no details should be returned.\ne.Update(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	observed := &camunda.Cluster{ClusterSpec: camunda.ClusterSpec{
+		Channel:    "channel",
+		Generation: "generation",
+		Region:     "region",
+		PlanType:   "plan",
+	}}
+
+	type args struct {
+		p *v1beta1.ClusterParameters
+		c *camunda.Cluster
+	}
+
+	type want struct {
+		p  *v1beta1.ClusterParameters
+		li bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllEmpty": {
+			reason: "All empty parameters should be filled from the observed cluster.",
+			args: args{
+				p: &v1beta1.ClusterParameters{},
+				c: observed,
+			},
+			want: want{
+				p: &v1beta1.ClusterParameters{
+					Channel:    "channel",
+					Generation: "generation",
+					Region:     "region",
+					PlanType:   "plan",
+				},
+				li: true,
+			},
+		},
+		"SomeSet": {
+			reason: "Parameters that are already set should not be overwritten.",
+			args: args{
+				p: &v1beta1.ClusterParameters{
+					Channel: "my-channel",
+					Region:  "my-region",
+				},
+				c: observed,
+			},
+			want: want{
+				p: &v1beta1.ClusterParameters{
+					Channel:    "my-channel",
+					Generation: "generation",
+					Region:     "my-region",
+					PlanType:   "plan",
+				},
+				li: true,
+			},
+		},
+		"AllSet": {
+			reason: "Nothing should be late-initialized when all parameters are set.",
+			args: args{
+				p: &v1beta1.ClusterParameters{
+					Channel:    "my-channel",
+					Generation: "my-generation",
+					Region:     "my-region",
+					PlanType:   "my-plan",
+				},
+				c: observed,
+			},
+			want: want{
+				p: &v1beta1.ClusterParameters{
+					Channel:    "my-channel",
+					Generation: "my-generation",
+					Region:     "my-region",
+					PlanType:   "my-plan",
+				},
+				li: false,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			li := lateInitialize(tc.args.p, tc.args.c)
+			if diff := cmp.Diff(tc.want.li, li); diff != "" {
+				t.Errorf("\n%s\nlateInitialize(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, tc.args.p); diff != "" {
+				t.Errorf("\n%s\nlateInitialize(...): -want parameters, +got parameters:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// Two cluster scoped Clients, a namespaced Client in namespace "team" and
+	// two in namespace "other" reference the cluster.
+	dependents := func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		switch l := list.(type) {
+		case *clientv1beta1.ClientList:
+			l.Items = []clientv1beta1.Client{{}, {}}
+		case *namespacedv1beta1.ClientList:
+			for _, ns := range []string{"team", "other", "other"} {
+				if lo.Namespace == "" || lo.Namespace == ns {
+					l.Items = append(l.Items, namespacedv1beta1.Client{ObjectMeta: metav1.ObjectMeta{Namespace: ns}})
+				}
+			}
+		}
+		return nil
+	}
+
+	type fields struct {
+		kube   client.Client
+		faults map[string]fake.Fault
+	}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		args   args
+		want   error
+	}{
+		"DeletionProtected": {
+			reason: "A Cluster with deletion protection should not be deleted.",
+			args: args{
+				ctx: context.Background(),
+				mg: &v1beta1.Cluster{Spec: v1beta1.ClusterSpec{
+					ForProvider: v1beta1.ClusterParameters{DeletionProtection: true},
+				}},
+			},
+			want: errors.New(errDeletionProtected),
+		},
+		"ListClientsError": {
+			reason: "Errors listing the dependent Clients should be returned.",
+			fields: fields{
+				kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  &v1beta1.Cluster{},
+			},
+			want: errors.Wrap(errBoom, errListClients),
+		},
+		"DependentClientsBlock": {
+			reason: "A cluster scoped Cluster should not be deleted while cluster scoped Clients reference it.",
+			fields: fields{
+				kube: &test.MockClient{MockList: dependents},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  &v1beta1.Cluster{},
+			},
+			want: errors.Errorf(errDependentClients, 2),
+		},
+		"NamespacedDependentClientsBlock": {
+			reason: "A namespaced Cluster should only wait for the Clients in its own namespace.",
+			fields: fields{
+				kube: &test.MockClient{MockList: dependents},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  &namespacedv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team"}},
+			},
+			want: errors.Errorf(errDependentClients, 1),
+		},
+		"DependentClientsCascade": {
+			reason: "The Clients that reference a Cluster should be deleted before the Cluster.",
+			fields: fields{
+				kube: &test.MockClient{
+					MockList:   dependents,
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: &v1beta1.Cluster{Spec: v1beta1.ClusterSpec{
+					ForProvider: v1beta1.ClusterParameters{DependentClientsPolicy: v1beta1.DependentClientsCascade},
+				}},
+			},
+			want: errors.Errorf(errDependentClients, 2),
+		},
+		"NamespacedDependentClientsCascade": {
+			reason: "A namespaced Cluster should only delete the Clients in its own namespace.",
+			fields: fields{
+				kube: &test.MockClient{
+					MockList: dependents,
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetNamespace() != "team" {
+							return errBoom
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: &namespacedv1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team"},
+					Spec: namespacedv1beta1.ClusterSpec{
+						ForProvider: v1beta1.ClusterParameters{DependentClientsPolicy: v1beta1.DependentClientsCascade},
+					},
+				},
+			},
+			want: errors.Errorf(errDependentClients, 1),
+		},
+		"Deleted": {
+			reason: "A Cluster without dependent Clients should be deleted.",
+			fields: fields{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil)},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}},
+			},
+			want: nil,
+		},
+		"AlreadyGone": {
+			reason: "Deleting a Cluster whose cluster is already gone should succeed.",
+			fields: fields{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil)},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: "gone"}}},
+			},
+			want: nil,
+		},
+		"DeleteError": {
+			reason: "Errors deleting the cluster should be returned.",
+			fields: fields{
+				kube:   &test.MockClient{MockList: test.NewMockListFn(nil)},
+				faults: map[string]fake.Fault{"DELETE /clusters/{id}": {StatusCode: http.StatusInternalServerError}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}},
+			},
+			want: errors.Wrap(errors.New("500 Internal Server Error"), errDeleteCluster),
+		},
+		"DependentClientsCascadeError": {
+			reason: "Errors deleting the Clients that reference a Cluster should be returned.",
+			fields: fields{
+				kube: &test.MockClient{
+					MockList:   dependents,
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: &v1beta1.Cluster{Spec: v1beta1.ClusterSpec{
+					ForProvider: v1beta1.ClusterParameters{DependentClientsPolicy: v1beta1.DependentClientsCascade},
+				}},
+			},
+			want: errors.Wrap(errBoom, errDeleteClient),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := fake.NewServer(fake.WithClusters(existing))
+			defer srv.Close()
+			e := newExternal(t, srv, tc.fields.kube)
+			for endpoint, f := range tc.fields.faults {
+				srv.Inject(endpoint, f)
+			}
+
+			err := e.Delete(logr.NewContext(tc.args.ctx, logr.Discard()), tc.args.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, exists := srv.Cluster(meta.GetExternalName(tc.args.mg)); tc.want == nil && exists {
+				t.Errorf("\n%s\ne.Delete(...): the cluster still exists", tc.reason)
+			}
+		})
+	}
+}
+
+// A recorder records the events of a test.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+func TestDeleteProtected(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+	}{
+		"Field": {
+			reason: "A Cluster protected by its deletionProtection field should report why it is not deleted.",
+			mg:     &v1beta1.Cluster{Spec: v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{DeletionProtection: true}}},
+		},
+		"Annotation": {
+			reason: "A namespaced Cluster protected by its annotation should report why it is not deleted.",
+			mg: &namespacedv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "team",
+				Annotations: map[string]string{v1beta1.AnnotationKeyDeletionProtection: "true"},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			e := &external{record: r}
+			err := e.Delete(logr.NewContext(context.Background(), logr.Discard()), tc.mg)
+			if diff := cmp.Diff(errors.New(errDeletionProtected), err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			want := v1beta1.ProtectedFromDeletion(errDeletionProtected)
+			if diff := cmp.Diff(want, tc.mg.GetCondition(v1beta1.TypeDeletionProtected), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+			wantEvents := []event.Event{event.Warning(reasonDeletionProtected, errors.New(errDeletionProtected))}
+			if diff := cmp.Diff(wantEvents, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClearDeletionProtected(t *testing.T) {
+	protected := v1beta1.ProtectedFromDeletion(errDeletionProtected)
+
+	cases := map[string]struct {
+		reason     string
+		cr         *v1beta1.Cluster
+		conditions []xpv1.Condition
+		want       []xpv1.Condition
+	}{
+		"NeverBlocked": {
+			reason: "A Cluster whose deletion was never blocked should not get the condition.",
+			cr:     &v1beta1.Cluster{},
+		},
+		"StillProtected": {
+			reason:     "A Cluster that is still protected should keep the condition.",
+			cr:         &v1beta1.Cluster{Spec: v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{DeletionProtection: true}}},
+			conditions: []xpv1.Condition{protected},
+			want:       []xpv1.Condition{protected},
+		},
+		"ProtectionLifted": {
+			reason:     "A Cluster whose protection was lifted should no longer report it.",
+			cr:         &v1beta1.Cluster{},
+			conditions: []xpv1.Condition{protected},
+			want:       []xpv1.Condition{v1beta1.UnprotectedFromDeletion()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.cr.SetConditions(tc.conditions...)
+			clearDeletionProtected(tc.cr)
+			if diff := cmp.Diff(tc.want, tc.cr.Status.Conditions, test.EquateConditions(), cmpopts.IgnoreTypes(metav1.Time{})); diff != "" {
+				t.Errorf("\n%s\nclearDeletionProtected(...): -want conditions, +got conditions:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeleteInvalidatesCache(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+	}{
+		"Deleted": {
+			reason: "The cached clusters should be invalidated once a cluster was deleted.",
+		},
+		"AlreadyGone": {
+			reason: "The cached clusters should be invalidated if the cluster was already gone.",
+			err:    camunda.NotFoundError{Err: errors.New("404 Not Found")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lists := 0
+			api := &fake.MockAPI{
+				MockListClusters: func(_ context.Context) ([]camunda.Cluster, error) {
+					lists++
+					return []camunda.Cluster{{ID: clusterID}}, nil
+				},
+				MockDeleteCluster: func(_ context.Context, _ string) error { return tc.err },
+			}
+			e := &external{service: api, cache: camunda.NewCache(time.Hour), kube: &test.MockClient{MockList: test.NewMockListFn(nil)}}
+			ctx := logr.NewContext(context.Background(), logr.Discard())
+			if _, _, err := e.cache.GetCluster(ctx, api, clusterID); err != nil {
+				t.Fatalf("e.cache.GetCluster(...): unexpected error: %v", err)
+			}
+
+			cr := &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: clusterID}}}
+			if err := e.Delete(ctx, cr); err != nil {
+				t.Fatalf("\n%s\ne.Delete(...): unexpected error: %v", tc.reason, err)
+			}
+			if _, _, err := e.cache.GetCluster(ctx, api, clusterID); err != nil {
+				t.Fatalf("e.cache.GetCluster(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(2, lists); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want cluster lists, +got cluster lists:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	pi := poll.Intervals{Busy: 10 * time.Second, Idle: 10 * time.Minute}
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   time.Duration
+	}{
+		"NotObserved": {
+			reason: "A cluster that was not observed yet should be polled at the busy interval.",
+			mg:     &v1beta1.Cluster{},
+			want:   pi.Busy,
+		},
+		"Creating": {
+			reason: "A cluster that is being created should be polled at the busy interval.",
+			mg:     &v1beta1.Cluster{Status: v1beta1.ClusterStatus{AtProvider: v1beta1.ClusterObservation{Health: v1beta1.ClusterHealth{Ready: string(camunda.Creating)}}}},
+			want:   pi.Busy,
+		},
+		"Deleting": {
+			reason: "A cluster that is being deleted should be polled at the busy interval.",
+			mg: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status:     v1beta1.ClusterStatus{AtProvider: v1beta1.ClusterObservation{Health: v1beta1.ClusterHealth{Ready: string(camunda.Healthy)}}},
+			},
+			want: pi.Busy,
+		},
+		"Healthy": {
+			reason: "A healthy cluster should be polled at the idle interval.",
+			mg:     &v1beta1.Cluster{Status: v1beta1.ClusterStatus{AtProvider: v1beta1.ClusterObservation{Health: v1beta1.ClusterHealth{Ready: string(camunda.Healthy)}}}},
+			want:   pi.Idle,
+		},
+		"NamespacedHealthy": {
+			reason: "A healthy namespaced cluster should be polled at the idle interval.",
+			mg:     &namespacedv1beta1.Cluster{Status: namespacedv1beta1.ClusterStatus{AtProvider: v1beta1.ClusterObservation{Health: v1beta1.ClusterHealth{Ready: string(camunda.Healthy)}}}},
+			want:   pi.Idle,
+		},
+		"Unhealthy": {
+			reason: "An unhealthy cluster should be polled at the default interval.",
+			mg:     &v1beta1.Cluster{Status: v1beta1.ClusterStatus{AtProvider: v1beta1.ClusterObservation{Health: v1beta1.ClusterHealth{Ready: string(camunda.Unhealthy)}}}},
+			want:   time.Minute,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := pollInterval(pi)(tc.mg, time.Minute)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npollInterval(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMetricsName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   string
+	}{
+		"ClusterScoped": {
+			reason: "A cluster scoped Cluster should be recorded by its name.",
+			mg:     &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"}},
+			want:   "cool-cluster",
+		},
+		"Namespaced": {
+			reason: "A namespaced Cluster should be recorded by its namespace and name.",
+			mg:     &namespacedv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cool-cluster"}},
+			want:   "team-a/cool-cluster",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := metricsName(tc.mg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nmetricsName(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordComponentHealth(t *testing.T) {
+	// Other tests observe clusters too, so start from a clean slate.
+	metrics.ClusterComponentHealthy.Reset()
+	recordComponentHealth("test", camunda.ClusterStatus{Zeebe: camunda.Healthy, Operate: camunda.Unhealthy})
+
+	cases := map[string]struct {
+		reason    string
+		component string
+		want      float64
+	}{
+		"Healthy": {
+			reason:    "A healthy component should be recorded as 1.",
+			component: "zeebe",
+			want:      1,
+		},
+		"Unhealthy": {
+			reason:    "An unhealthy component should be recorded as 0.",
+			component: "operate",
+			want:      0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := testutil.ToFloat64(metrics.ClusterComponentHealthy.WithLabelValues("test", tc.component))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrecordComponentHealth(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		// Only the two components that reported a status should have a series.
+		if diff := cmp.Diff(2, testutil.CollectAndCount(metrics.ClusterComponentHealthy)); diff != "" {
+			t.Errorf("\nComponents without a status should not be recorded.\nrecordComponentHealth(...): -want, +got:\n%s\n", diff)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+)
+
+const (
+	healthTimeout       = 1 * time.Minute
+	healthCheckInterval = 5 * time.Minute
+
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+	errUpdateStatus = "cannot patch ProviderConfig status"
+)
+
+// Event reasons.
+const (
+	reasonCheckHealth  event.Reason = "CheckHealth"
+	reasonHealthPassed event.Reason = "HealthCheckPassed"
+)
+
+// A configKind is a kind of ProviderConfig whose credentials are checked.
+type configKind struct {
+	// groupKind of the ProviderConfig.
+	groupKind string
+
+	// object returns an empty ProviderConfig of the kind.
+	object func() client.Object
+
+	// resolve returns the supplied ProviderConfig of the kind as it is
+	// resolved for managed resources, along with its status.
+	resolve func(pc client.Object) (*Resolved, *v1beta1.ProviderConfigStatus)
+}
+
+var (
+	clusterConfigs = configKind{
+		groupKind: v1beta1.ProviderConfigGroupKind,
+		object:    func() client.Object { return &v1beta1.ProviderConfig{} },
+		resolve: func(o client.Object) (*Resolved, *v1beta1.ProviderConfigStatus) {
+			pc := o.(*v1beta1.ProviderConfig)
+			return &Resolved{Key: configKey("", pc.GetName()), Spec: pc.Spec}, &pc.Status
+		},
+	}
+
+	namespacedConfigs = configKind{
+		groupKind: namespacedv1beta1.ProviderConfigGroupKind,
+		object:    func() client.Object { return &namespacedv1beta1.ProviderConfig{} },
+		resolve: func(o client.Object) (*Resolved, *v1beta1.ProviderConfigStatus) {
+			pc := o.(*namespacedv1beta1.ProviderConfig)
+			return &Resolved{Key: configKey(pc.GetNamespace(), pc.GetName()), Spec: pc.Spec, namespace: pc.GetNamespace()}, &pc.Status
+		},
+	}
+)
+
+// setupHealth adds controllers that periodically check whether the
+// credentials of cluster scoped and namespaced ProviderConfigs are accepted by
+// the Console API.
+func setupHealth(mgr ctrl.Manager, o controller.Options) error {
+	for _, k := range []configKind{clusterConfigs, namespacedConfigs} {
+		name := "health/" + strings.ToLower(k.groupKind)
+
+		r := &healthReconciler{
+			kube:   mgr.GetClient(),
+			kind:   k,
+			check:  camunda.CheckHealth,
+			forget: camunda.Forget,
+			log:    o.Logger.WithValues("controller", name),
+			record: event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+		}
+
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			WithOptions(o.ForControllerRuntime()).
+			// Status updates do not change the generation, so that updating
+			// the health status does not trigger another check.
+			For(k.object(), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+			Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A healthReconciler checks the credentials of ProviderConfigs of one kind.
+type healthReconciler struct {
+	kube   client.Client
+	kind   configKind
+	check  func(ctx context.Context, creds []byte) (*camunda.Health, error)
+	forget func(key string)
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile checks the credentials of a ProviderConfig and records the result
+// in its status.
+func (r *healthReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	// The Service and observation cache of a deleted ProviderConfig are
+	// dropped, so that they do not outlive it.
+	key := configKey(req.Namespace, req.Name)
+	pc := r.kind.object()
+	if err := r.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.forget(key)
+		}
+		// There's no need to requeue if the ProviderConfig no longer exists.
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPC)
+	}
+
+	if meta.WasDeleted(pc) {
+		r.forget(key)
+		return reconcile.Result{}, nil
+	}
+
+	// The status is patched rather than updated, so that it does not conflict
+	// with the ProviderConfig reconciler tracking the ProviderConfig's users.
+	orig := pc.DeepCopyObject().(client.Object)
+	resolved, status := r.kind.resolve(pc)
+	now := metav1.Now()
+	// Details of an earlier check are dropped, so that they are not reported
+	// for credentials that no longer work.
+	status.Health = v1beta1.ProviderConfigHealth{LastCheckTime: &now}
+
+	h, err := r.checkHealth(ctx, resolved)
+	switch {
+	case camunda.IsUnauthorized(err):
+		log.Debug("ProviderConfig credentials were rejected", "error", err)
+		r.record.Event(pc, event.Warning(reasonCheckHealth, err))
+		status.SetConditions(v1beta1.Unauthorized(err.Error()))
+	case err != nil:
+		log.Debug("Cannot check ProviderConfig health", "error", err)
+		r.record.Event(pc, event.Warning(reasonCheckHealth, err))
+		status.SetConditions(xpv1.Unavailable().WithMessage(err.Error()))
+	default:
+		if status.GetCondition(xpv1.TypeReady).Reason != xpv1.ReasonAvailable {
+			r.record.Event(pc, event.Normal(reasonHealthPassed, "Successfully authenticated against the Console API"))
+		}
+		status.SetConditions(xpv1.Available())
+		status.Health.OrganizationID = h.OrganizationID
+		status.Health.Audience = h.Audience
+		status.Health.TokenExpiry = &metav1.Time{Time: h.Token.Expiry}
+	}
+
+	return reconcile.Result{RequeueAfter: healthCheckInterval}, errors.Wrap(r.kube.Status().Patch(ctx, pc, client.MergeFrom(orig)), errUpdateStatus)
+}
+
+func (r *healthReconciler) checkHealth(ctx context.Context, pc *Resolved) (*camunda.Health, error) {
+	data, err := pc.Credentials(ctx, r.kube)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+	return r.check(ctx, data)
+}
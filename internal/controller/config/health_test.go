@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+)
+
+func TestHealthReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	expiry := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// getPC returns a ProviderConfig that passed an earlier health check.
+	getPC := test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.SetName("default")
+		pc := obj.(*v1beta1.ProviderConfig)
+		pc.Spec.Credentials.Source = xpv1.CredentialsSourceNone
+		pc.Status.Health = v1beta1.ProviderConfigHealth{
+			OrganizationID: "old-org",
+			Audience:       "old-audience",
+			TokenExpiry:    &metav1.Time{Time: expiry.Add(-time.Hour)},
+		}
+		return nil
+	})
+
+	// ignoreTimes ignores the timestamps that are set to the current time.
+	ignoreTimes := cmpopts.IgnoreFields(v1beta1.ProviderConfigHealth{}, "LastCheckTime")
+
+	type fields struct {
+		kind  configKind
+		kube  *test.MockClient
+		check func(ctx context.Context, creds []byte) (*camunda.Health, error)
+	}
+
+	type want struct {
+		r      reconcile.Result
+		err    error
+		status *v1beta1.ProviderConfigStatus
+
+		// forgotten is the key of the ProviderConfig whose Service and
+		// observation cache should be dropped.
+		forgotten string
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		req    reconcile.Request
+		want   want
+	}{
+		"NotFound": {
+			reason: "We should not requeue if the ProviderConfig no longer exists, and forget its state.",
+			fields: fields{
+				kind: namespacedConfigs,
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			},
+			req:  reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team", Name: "default"}},
+			want: want{r: reconcile.Result{}, forgotten: "team/default"},
+		},
+		"Deleted": {
+			reason: "The state of a ProviderConfig that is being deleted should be forgotten.",
+			fields: fields{
+				kind: clusterConfigs,
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					now := metav1.Now()
+					obj.SetDeletionTimestamp(&now)
+					return nil
+				})},
+			},
+			req:  reconcile.Request{NamespacedName: types.NamespacedName{Name: "default"}},
+			want: want{r: reconcile.Result{}, forgotten: "default"},
+		},
+		"GetError": {
+			reason: "Errors getting the ProviderConfig should be returned.",
+			fields: fields{
+				kind: clusterConfigs,
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetPC)},
+		},
+		"Unauthorized": {
+			reason: "Rejected credentials should be reported as Unauthorized, without the details of an earlier check.",
+			fields: fields{
+				kind: clusterConfigs,
+				kube: &test.MockClient{MockGet: getPC},
+				check: func(_ context.Context, _ []byte) (*camunda.Health, error) {
+					return nil, camunda.UnauthorizedError{Err: errBoom}
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: healthCheckInterval},
+				status: func() *v1beta1.ProviderConfigStatus {
+					s := &v1beta1.ProviderConfigStatus{}
+					s.SetConditions(v1beta1.Unauthorized(camunda.UnauthorizedError{Err: errBoom}.Error()))
+					return s
+				}(),
+			},
+		},
+		"Unavailable": {
+			reason: "Other errors should be reported as Unavailable, without the details of an earlier check.",
+			fields: fields{
+				kind: clusterConfigs,
+				kube: &test.MockClient{MockGet: getPC},
+				check: func(_ context.Context, _ []byte) (*camunda.Health, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: healthCheckInterval},
+				status: func() *v1beta1.ProviderConfigStatus {
+					s := &v1beta1.ProviderConfigStatus{}
+					s.SetConditions(xpv1.Unavailable().WithMessage(errBoom.Error()))
+					return s
+				}(),
+			},
+		},
+		"Available": {
+			reason: "Accepted credentials should be reported as Available along with the organization.",
+			fields: fields{
+				kind: clusterConfigs,
+				kube: &test.MockClient{MockGet: getPC},
+				check: func(_ context.Context, _ []byte) (*camunda.Health, error) {
+					return &camunda.Health{
+						OrganizationID: "org",
+						Audience:       "api.cloud.camunda.io",
+						Token:          &oauth2.Token{Expiry: expiry},
+					}, nil
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: healthCheckInterval},
+				status: func() *v1beta1.ProviderConfigStatus {
+					s := &v1beta1.ProviderConfigStatus{
+						Health: v1beta1.ProviderConfigHealth{
+							OrganizationID: "org",
+							Audience:       "api.cloud.camunda.io",
+							TokenExpiry:    &metav1.Time{Time: expiry},
+						},
+					}
+					s.SetConditions(xpv1.Available())
+					return s
+				}(),
+			},
+		},
+		"Namespaced": {
+			reason: "A namespaced ProviderConfig should be checked with the Secret of its own namespace.",
+			fields: fields{
+				kind: namespacedConfigs,
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *namespacedv1beta1.ProviderConfig:
+						o.SetNamespace("team")
+						o.SetName("default")
+						o.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+						o.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{
+							SecretReference: xpv1.SecretReference{Namespace: "other", Name: "creds"},
+							Key:             "credentials",
+						}
+					case *corev1.Secret:
+						if key.Namespace != "team" {
+							return errBoom
+						}
+						o.Data = map[string][]byte{"credentials": []byte("creds")}
+					}
+					return nil
+				}},
+				check: func(_ context.Context, creds []byte) (*camunda.Health, error) {
+					if string(creds) != "creds" {
+						return nil, errBoom
+					}
+					return &camunda.Health{OrganizationID: "org", Token: &oauth2.Token{Expiry: expiry}}, nil
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: healthCheckInterval},
+				status: func() *v1beta1.ProviderConfigStatus {
+					s := &v1beta1.ProviderConfigStatus{
+						Health: v1beta1.ProviderConfigHealth{
+							OrganizationID: "org",
+							TokenExpiry:    &metav1.Time{Time: expiry},
+						},
+					}
+					s.SetConditions(xpv1.Available())
+					return s
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *v1beta1.ProviderConfigStatus
+			tc.fields.kube.MockStatusPatch = func(_ context.Context, obj client.Object, p client.Patch, _ ...client.SubResourcePatchOption) error {
+				// An optimistic lock would conflict with the ProviderConfig
+				// reconciler.
+				if p.Type() != types.MergePatchType {
+					t.Errorf("\n%s\nr.Reconcile(...): want a merge patch, got %s", tc.reason, p.Type())
+				}
+				_, got = tc.fields.kind.resolve(obj)
+				return nil
+			}
+
+			forgotten := ""
+			r := &healthReconciler{
+				kube:   tc.fields.kube,
+				kind:   tc.fields.kind,
+				check:  tc.fields.check,
+				forget: func(key string) { forgotten = key },
+				log:    logging.NewNopLogger(),
+				record: event.NewNopRecorder(),
+			}
+			res, err := r.Reconcile(context.Background(), tc.req)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, res); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, got, test.EquateConditions(), ignoreTimes); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.forgotten, forgotten); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want forgotten, +got forgotten:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+// cacheSyncTimeout bounds how long a readiness check waits for the informer
+// caches to sync.
+const cacheSyncTimeout = 1 * time.Second
+
+const (
+	errListPCs       = "cannot list ProviderConfigs"
+	errNoHealthyPC   = "none of the %d ProviderConfigs can reach the Console API"
+	errCachesNotSync = "informer caches are not synced"
+)
+
+// ConsoleReachable returns a readiness check that passes if at least one
+// cluster scoped or namespaced ProviderConfig passed its last health check,
+// i.e. can reach the Console API. It also passes if there are no
+// ProviderConfigs, since the provider must become ready before anybody can
+// create one.
+func ConsoleReachable(kube client.Reader) healthz.Checker {
+	return func(req *http.Request) error {
+		l := &v1beta1.ProviderConfigList{}
+		if err := kube.List(req.Context(), l); err != nil {
+			return errors.Wrap(err, errListPCs)
+		}
+		nl := &namespacedv1beta1.ProviderConfigList{}
+		if err := kube.List(req.Context(), nl); err != nil {
+			return errors.Wrap(err, errListPCs)
+		}
+		statuses := make([]v1beta1.ProviderConfigStatus, 0, len(l.Items)+len(nl.Items))
+		for _, pc := range l.Items {
+			statuses = append(statuses, pc.Status)
+		}
+		for _, pc := range nl.Items {
+			statuses = append(statuses, pc.Status)
+		}
+		if len(statuses) == 0 {
+			return nil
+		}
+		for _, s := range statuses {
+			if s.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue {
+				return nil
+			}
+		}
+		return errors.Errorf(errNoHealthyPC, len(statuses))
+	}
+}
+
+// CachesSynced returns a readiness check that passes once the supplied
+// function, typically a cache's WaitForCacheSync, reports that the informer
+// caches are synced.
+func CachesSynced(synced func(ctx context.Context) bool) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !synced(ctx) {
+			return errors.New(errCachesNotSync)
+		}
+		return nil
+	}
+}
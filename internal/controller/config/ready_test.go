@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+func TestConsoleReachable(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// withPCs lists cluster scoped and namespaced ProviderConfigs with the
+	// supplied conditions.
+	withPCs := func(cluster, namespaced []xpv1.Condition) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			switch l := obj.(type) {
+			case *v1beta1.ProviderConfigList:
+				for _, c := range cluster {
+					pc := v1beta1.ProviderConfig{}
+					pc.Status.SetConditions(c)
+					l.Items = append(l.Items, pc)
+				}
+			case *namespacedv1beta1.ProviderConfigList:
+				for _, c := range namespaced {
+					pc := namespacedv1beta1.ProviderConfig{}
+					pc.Status.SetConditions(c)
+					l.Items = append(l.Items, pc)
+				}
+			}
+			return nil
+		})
+	}
+
+	cases := map[string]struct {
+		reason string
+		list   test.MockListFn
+		want   error
+	}{
+		"ListError": {
+			reason: "The check should fail if ProviderConfigs cannot be listed.",
+			list:   test.NewMockListFn(errBoom),
+			want:   errors.Wrap(errBoom, errListPCs),
+		},
+		"NoProviderConfigs": {
+			reason: "The check should pass if there are no ProviderConfigs.",
+			list:   withPCs(nil, nil),
+		},
+		"OneHealthy": {
+			reason: "The check should pass if at least one ProviderConfig is healthy.",
+			list:   withPCs([]xpv1.Condition{xpv1.Unavailable(), xpv1.Available()}, nil),
+		},
+		"OneNamespacedHealthy": {
+			reason: "The check should pass if at least one namespaced ProviderConfig is healthy.",
+			list:   withPCs([]xpv1.Condition{xpv1.Unavailable()}, []xpv1.Condition{xpv1.Available()}),
+		},
+		"NoneHealthy": {
+			reason: "The check should fail if no ProviderConfig is healthy.",
+			list:   withPCs([]xpv1.Condition{xpv1.Unavailable()}, []xpv1.Condition{v1beta1.Unauthorized("denied")}),
+			want:   errors.Errorf(errNoHealthyPC, 2),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ConsoleReachable(&test.MockClient{MockList: tc.list})(httptest.NewRequest("GET", "/readyz", nil))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConsoleReachable(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCachesSynced(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		synced bool
+		want   error
+	}{
+		"Synced": {
+			reason: "The check should pass once the caches are synced.",
+			synced: true,
+		},
+		"NotSynced": {
+			reason: "The check should fail while the caches are not synced.",
+			want:   errors.New(errCachesNotSync),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := CachesSynced(func(context.Context) bool { return tc.synced })(httptest.NewRequest("GET", "/readyz", nil))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCachesSynced(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
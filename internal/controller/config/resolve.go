@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+const errNamespacedSource = "the credentials of a namespaced ProviderConfig must come from a Secret"
+
+// A Resolved ProviderConfig is the ProviderConfig of a managed resource of
+// either scope.
+type Resolved struct {
+	// Key identifies the ProviderConfig. It is the name of a ProviderConfig
+	// and the namespace and name of a namespaced ProviderConfig.
+	Key string
+
+	// Spec of the ProviderConfig.
+	Spec v1beta1.ProviderConfigSpec
+
+	namespace string
+}
+
+// Resolve returns the ProviderConfig of the supplied managed resource. A
+// namespaced managed resource uses the namespaced ProviderConfig of the
+// referenced name in its own namespace.
+func Resolve(ctx context.Context, kube client.Reader, mg resource.Managed) (*Resolved, error) {
+	name := mg.GetProviderConfigReference().Name
+	ns := mg.GetNamespace()
+	if ns == "" {
+		pc := &v1beta1.ProviderConfig{}
+		if err := kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+			return nil, err
+		}
+		return &Resolved{Key: configKey("", name), Spec: pc.Spec}, nil
+	}
+	pc := &namespacedv1beta1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, pc); err != nil {
+		return nil, err
+	}
+	return &Resolved{Key: configKey(ns, name), Spec: pc.Spec, namespace: ns}, nil
+}
+
+// configKey returns the Key of the ProviderConfig with the supplied namespace
+// and name. The namespace of a cluster scoped ProviderConfig is empty.
+func configKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// Credentials returns the credentials of the ProviderConfig. A namespaced
+// ProviderConfig may only read them from a Secret in its own namespace, so
+// that it cannot use the provider's own identity or another tenant's Secrets.
+func (r *Resolved) Credentials(ctx context.Context, kube client.Client) ([]byte, error) {
+	cd := r.Spec.Credentials
+	if r.namespace == "" {
+		return resource.CommonCredentialExtractor(ctx, cd.Source, kube, cd.CommonCredentialSelectors)
+	}
+	if cd.Source != xpv1.CredentialsSourceSecret {
+		return nil, errors.New(errNamespacedSource)
+	}
+	sel := cd.CommonCredentialSelectors
+	if sel.SecretRef != nil {
+		ref := *sel.SecretRef
+		ref.Namespace = r.namespace
+		sel.SecretRef = &ref
+	}
+	return resource.CommonCredentialExtractor(ctx, cd.Source, kube, sel)
+}
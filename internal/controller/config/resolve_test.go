@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	clusterv1beta1 "github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+func TestResolve(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := &xpv1.Reference{Name: "cool-pc"}
+
+	// getPC only returns ProviderConfigs of the expected kind and namespace.
+	getPC := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch pc := obj.(type) {
+		case *v1beta1.ProviderConfig:
+			if key.Namespace != "" {
+				return errBoom
+			}
+			pc.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+		case *namespacedv1beta1.ProviderConfig:
+			if key.Namespace != "team-a" {
+				return errBoom
+			}
+			pc.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+		}
+		return nil
+	}
+
+	type want struct {
+		r   *Resolved
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Reader
+		mg     resource.Managed
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting the ProviderConfig should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			mg:     &clusterv1beta1.Cluster{Spec: clusterv1beta1.ClusterSpec{ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: ref}}},
+			want:   want{err: errBoom},
+		},
+		"ClusterScoped": {
+			reason: "A cluster scoped managed resource should use a ProviderConfig.",
+			kube:   &test.MockClient{MockGet: getPC},
+			mg:     &clusterv1beta1.Cluster{Spec: clusterv1beta1.ClusterSpec{ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: ref}}},
+			want: want{r: &Resolved{
+				Key:  "cool-pc",
+				Spec: v1beta1.ProviderConfigSpec{Credentials: v1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret}},
+			}},
+		},
+		"Namespaced": {
+			reason: "A namespaced managed resource should use the namespaced ProviderConfig in its namespace.",
+			kube:   &test.MockClient{MockGet: getPC},
+			mg: &namespacedv1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"},
+				Spec:       namespacedv1beta1.ClusterSpec{ResourceSpec: namespacedv1beta1.ResourceSpec{ProviderConfigReference: ref}},
+			},
+			want: want{r: &Resolved{
+				Key:       "team-a/cool-pc",
+				Spec:      v1beta1.ProviderConfigSpec{Credentials: v1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret}},
+				namespace: "team-a",
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Resolve(context.Background(), tc.kube, tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got, cmp.AllowUnexported(Resolved{})); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	creds := []byte(`{"client_id":"id","client_secret":"secret"}`)
+
+	// getSecret returns the credentials only from the namespace "team-a".
+	getSecret := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Namespace != "team-a" {
+			return errors.New("wrong namespace")
+		}
+		obj.(*corev1.Secret).Data = map[string][]byte{"credentials": creds}
+		return nil
+	}
+
+	selectors := xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+		SecretReference: xpv1.SecretReference{Name: "creds", Namespace: "team-b"},
+		Key:             "credentials",
+	}}
+
+	type want struct {
+		creds []byte
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		r      *Resolved
+		want   want
+	}{
+		"NamespacedNotSecret": {
+			reason: "A namespaced ProviderConfig should not use credentials that do not come from a Secret.",
+			r: &Resolved{
+				Spec:      v1beta1.ProviderConfigSpec{Credentials: v1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceInjectedIdentity}},
+				namespace: "team-a",
+			},
+			want: want{err: errors.New(errNamespacedSource)},
+		},
+		"NamespacedSecret": {
+			reason: "A namespaced ProviderConfig should read its Secret from its own namespace.",
+			r: &Resolved{
+				Spec:      v1beta1.ProviderConfigSpec{Credentials: v1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: selectors}},
+				namespace: "team-a",
+			},
+			want: want{creds: creds},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.r.Credentials(context.Background(), &test.MockClient{MockGet: getSecret})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Credentials(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("\n%s\nr.Credentials(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if tc.r.Spec.Credentials.SecretRef != nil && tc.r.Spec.Credentials.SecretRef.Namespace != "team-b" {
+				t.Errorf("\n%s\nr.Credentials(...): the ProviderConfig's spec must not be modified", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/providerconfig"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+)
+
+const (
+	usageTimeout   = 2 * time.Minute
+	usageShortWait = 30 * time.Second
+	finalizerInUse = "in-use.crossplane.io"
+
+	errMissingPCRef = "managed resource does not reference a ProviderConfig"
+	errApplyPCU     = "cannot apply ProviderConfigUsage"
+	errListPCUs     = "cannot list ProviderConfigUsages"
+	errDeletePCU    = "cannot delete ProviderConfigUsage"
+	errUpdatePC     = "cannot update ProviderConfig"
+)
+
+// Event reasons.
+const (
+	reasonAccount event.Reason = "UsageAccounting"
+)
+
+// A NamespacedUsageTracker tracks the usage of namespaced ProviderConfigs by
+// creating a ProviderConfigUsage in the namespace of each managed resource.
+// The tracker of crossplane-runtime only creates cluster scoped usages.
+type NamespacedUsageTracker struct {
+	c resource.Applicator
+}
+
+// NewNamespacedUsageTracker returns a NamespacedUsageTracker.
+func NewNamespacedUsageTracker(c client.Client) *NamespacedUsageTracker {
+	return &NamespacedUsageTracker{c: resource.NewAPIUpdatingApplicator(c)}
+}
+
+// Track that the supplied namespaced managed resource is using the
+// ProviderConfig of its namespace it references.
+func (u *NamespacedUsageTracker) Track(ctx context.Context, mg resource.Managed) error {
+	gvk := mg.GetObjectKind().GroupVersionKind()
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		return errors.New(errMissingPCRef)
+	}
+
+	pcu := &namespacedv1beta1.ProviderConfigUsage{}
+	pcu.SetNamespace(mg.GetNamespace())
+	pcu.SetName(string(mg.GetUID()))
+	pcu.SetLabels(map[string]string{xpv1.LabelKeyProviderName: ref.Name})
+	pcu.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(mg, gvk))})
+	pcu.SetProviderConfigReference(xpv1.Reference{Name: ref.Name})
+	pcu.SetResourceReference(xpv1.TypedReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       mg.GetName(),
+	})
+
+	err := u.c.Apply(ctx, pcu,
+		resource.MustBeControllableBy(mg.GetUID()),
+		resource.AllowUpdateIf(func(current, _ runtime.Object) bool {
+			return current.(resource.ProviderConfigUsage).GetProviderConfigReference() != pcu.GetProviderConfigReference()
+		}),
+	)
+	return errors.Wrap(resource.Ignore(resource.IsNotAllowed, err), errApplyPCU)
+}
+
+// setupUsage adds a controller that accounts for the usage of namespaced
+// ProviderConfigs, and keeps them from being deleted while in use.
+func setupUsage(mgr ctrl.Manager, o controller.Options) error {
+	name := providerconfig.ControllerName(namespacedv1beta1.ProviderConfigGroupKind)
+
+	r := &usageReconciler{
+		kube:   mgr.GetClient(),
+		log:    o.Logger.WithValues("controller", name),
+		record: event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&namespacedv1beta1.ProviderConfig{}).
+		Watches(&source.Kind{Type: &namespacedv1beta1.ProviderConfigUsage{}}, handler.EnqueueRequestsFromMapFunc(usedConfig)).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// usedConfig returns a request for the ProviderConfig a ProviderConfigUsage
+// refers to. Unlike a cluster scoped usage it refers to the ProviderConfig of
+// its own namespace.
+func usedConfig(o client.Object) []reconcile.Request {
+	pcu, ok := o.(resource.ProviderConfigUsage)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: pcu.GetNamespace(),
+		Name:      pcu.GetProviderConfigReference().Name,
+	}}}
+}
+
+// A usageReconciler accounts for the usage of namespaced ProviderConfigs. It
+// works like the ProviderConfig reconciler of crossplane-runtime, except that
+// it only counts the usages in a ProviderConfig's own namespace.
+type usageReconciler struct {
+	kube   client.Client
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a namespaced ProviderConfig by accounting for the managed
+// resources that are using it, and ensuring it cannot be deleted until it is
+// no longer in use.
+func (r *usageReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, usageTimeout)
+	defer cancel()
+
+	pc := &namespacedv1beta1.ProviderConfig{}
+	if err := r.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		// There's no need to requeue if the ProviderConfig no longer exists.
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPC)
+	}
+
+	l := &namespacedv1beta1.ProviderConfigUsageList{}
+	if err := r.kube.List(ctx, l, client.InNamespace(pc.GetNamespace()), client.MatchingLabels{xpv1.LabelKeyProviderName: pc.GetName()}); err != nil {
+		log.Debug(errListPCUs, "error", err)
+		r.record.Event(pc, event.Warning(reasonAccount, errors.Wrap(err, errListPCUs)))
+		return reconcile.Result{RequeueAfter: usageShortWait}, nil
+	}
+
+	users := int64(len(l.Items))
+	for i := range l.Items {
+		pcu := &l.Items[i]
+		if metav1.GetControllerOf(pcu) != nil {
+			continue
+		}
+		// A usage without a controller reference is stale; it is recreated
+		// the next time its managed resource connects.
+		if err := r.kube.Delete(ctx, pcu); resource.IgnoreNotFound(err) != nil {
+			log.Debug(errDeletePCU, "error", err)
+			r.record.Event(pc, event.Warning(reasonAccount, errors.Wrap(err, errDeletePCU)))
+			return reconcile.Result{RequeueAfter: usageShortWait}, nil
+		}
+		users--
+	}
+	log = log.WithValues("usages", users)
+
+	if meta.WasDeleted(pc) {
+		if users > 0 {
+			msg := "Blocking deletion while usages still exist"
+			log.Debug(msg)
+			r.record.Event(pc, event.Warning(reasonAccount, errors.New(msg)))
+
+			// The usages are watched, so the ProviderConfig is requeued when
+			// they are gone.
+			orig := pc.DeepCopy()
+			pc.SetUsers(users)
+			pc.SetConditions(providerconfig.Terminating().WithMessage(msg))
+			return reconcile.Result{}, errors.Wrap(r.kube.Status().Patch(ctx, pc, client.MergeFrom(orig)), errUpdateStatus)
+		}
+
+		meta.RemoveFinalizer(pc, finalizerInUse)
+		if err := r.kube.Update(ctx, pc); err != nil {
+			log.Debug(errUpdatePC, "error", err)
+			return reconcile.Result{RequeueAfter: usageShortWait}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+
+	meta.AddFinalizer(pc, finalizerInUse)
+	if err := r.kube.Update(ctx, pc); err != nil {
+		log.Debug(errUpdatePC, "error", err)
+		return reconcile.Result{RequeueAfter: usageShortWait}, nil
+	}
+
+	orig := pc.DeepCopy()
+	pc.SetUsers(users)
+	return reconcile.Result{}, errors.Wrap(r.kube.Status().Patch(ctx, pc, client.MergeFrom(orig)), errUpdateStatus)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/providerconfig"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+func TestNamespacedUsageTrackerTrack(t *testing.T) {
+	cr := &namespacedv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "cluster", UID: "uid"}}
+	cr.SetGroupVersionKind(namespacedv1beta1.ClusterGroupVersionKind)
+	cr.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+
+	var got *namespacedv1beta1.ProviderConfigUsage
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+		MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			got = obj.(*namespacedv1beta1.ProviderConfigUsage)
+			return nil
+		},
+	}
+	if err := NewNamespacedUsageTracker(kube).Track(context.Background(), cr); err != nil {
+		t.Fatalf("Track(...): unexpected error: %v", err)
+	}
+
+	want := &namespacedv1beta1.ProviderConfigUsage{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "team",
+			Name:            "uid",
+			Labels:          map[string]string{xpv1.LabelKeyProviderName: "default"},
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, namespacedv1beta1.ClusterGroupVersionKind))},
+		},
+		ProviderConfigUsage: xpv1.ProviderConfigUsage{
+			ProviderConfigReference: xpv1.Reference{Name: "default"},
+			ResourceReference: xpv1.TypedReference{
+				APIVersion: namespacedv1beta1.ClusterGroupVersionKind.GroupVersion().String(),
+				Kind:       namespacedv1beta1.ClusterKind,
+				Name:       "cluster",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe usage should be created in the namespace of the managed resource.\nTrack(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestUsageReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+	controlled := namespacedv1beta1.ProviderConfigUsage{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{{Controller: func() *bool { b := true; return &b }()}},
+	}}
+
+	type fields struct {
+		pc     namespacedv1beta1.ProviderConfig
+		usages []namespacedv1beta1.ProviderConfigUsage
+		delete error
+	}
+
+	type want struct {
+		pc      *namespacedv1beta1.ProviderConfig
+		deleted int
+		r       reconcile.Result
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		want   want
+	}{
+		"InUse": {
+			reason: "The usages of a ProviderConfig should be counted and a finalizer added.",
+			fields: fields{usages: []namespacedv1beta1.ProviderConfigUsage{controlled, controlled}},
+			want: want{pc: &namespacedv1beta1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "default", Finalizers: []string{finalizerInUse}},
+				Status:     v1beta1.ProviderConfigStatus{ProviderConfigStatus: xpv1.ProviderConfigStatus{Users: 2}},
+			}},
+		},
+		"StaleUsage": {
+			reason: "Usages without a controller reference should be deleted and not counted.",
+			fields: fields{usages: []namespacedv1beta1.ProviderConfigUsage{controlled, {}}},
+			want: want{
+				pc: &namespacedv1beta1.ProviderConfig{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "default", Finalizers: []string{finalizerInUse}},
+					Status:     v1beta1.ProviderConfigStatus{ProviderConfigStatus: xpv1.ProviderConfigStatus{Users: 1}},
+				},
+				deleted: 1,
+			},
+		},
+		"DeleteStaleUsageError": {
+			reason: "Errors deleting a stale usage should lead to a requeue.",
+			fields: fields{usages: []namespacedv1beta1.ProviderConfigUsage{{}}, delete: errBoom},
+			want: want{
+				deleted: 1,
+				r:       reconcile.Result{RequeueAfter: usageShortWait},
+			},
+		},
+		"DeletedInUse": {
+			reason: "The deletion of a ProviderConfig that is still in use should be blocked.",
+			fields: fields{
+				pc:     namespacedv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now, Finalizers: []string{finalizerInUse}}},
+				usages: []namespacedv1beta1.ProviderConfigUsage{controlled},
+			},
+			want: want{pc: func() *namespacedv1beta1.ProviderConfig {
+				pc := &namespacedv1beta1.ProviderConfig{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "default", DeletionTimestamp: &now, Finalizers: []string{finalizerInUse}},
+					Status:     v1beta1.ProviderConfigStatus{ProviderConfigStatus: xpv1.ProviderConfigStatus{Users: 1}},
+				}
+				pc.SetConditions(providerconfig.Terminating().WithMessage("Blocking deletion while usages still exist"))
+				return pc
+			}()},
+		},
+		"DeletedUnused": {
+			reason: "The finalizer of a deleted ProviderConfig should be removed once it is no longer in use.",
+			fields: fields{
+				pc: namespacedv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now, Finalizers: []string{finalizerInUse}}},
+			},
+			want: want{pc: &namespacedv1beta1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "default", DeletionTimestamp: &now, Finalizers: []string{}},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := tc.fields.pc.DeepCopy()
+			deleted := 0
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					tc.fields.pc.DeepCopyInto(obj.(*namespacedv1beta1.ProviderConfig))
+					obj.SetNamespace(key.Namespace)
+					obj.SetName(key.Name)
+					return nil
+				},
+				MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if lo.Namespace != "team" {
+						t.Errorf("\n%s\nr.Reconcile(...): usages listed in namespace %q, want %q", tc.reason, lo.Namespace, "team")
+					}
+					list.(*namespacedv1beta1.ProviderConfigUsageList).Items = tc.fields.usages
+					return nil
+				},
+				MockDelete: func(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+					deleted++
+					return tc.fields.delete
+				},
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					obj.(*namespacedv1beta1.ProviderConfig).DeepCopyInto(pc)
+					return nil
+				},
+				MockStatusPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+					obj.(*namespacedv1beta1.ProviderConfig).DeepCopyInto(pc)
+					return nil
+				},
+			}
+			r := &usageReconciler{kube: kube, log: logging.NewNopLogger(), record: event.NewNopRecorder()}
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team", Name: "default"}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.pc != nil {
+				if diff := cmp.Diff(tc.want.pc, pc, test.EquateConditions(), cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("\n%s\nr.Reconcile(...): -want ProviderConfig, +got ProviderConfig:\n%s\n", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want deleted usages, +got deleted usages:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
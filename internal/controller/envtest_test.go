@@ -0,0 +1,525 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	console "github.com/sijoma/console-customer-api-go"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-camunda/apis"
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	clusterv1beta1 "github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	apisv1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+)
+
+// The integration tests run the controllers against a local API server and a
+// fake Console API. They are skipped unless KUBEBUILDER_ASSETS points to the
+// etcd and kube-apiserver binaries, for example as installed by:
+//
+//	setup-envtest use -p path
+
+const (
+	systemNamespace = "crossplane-system"
+
+	// finalizer is the finalizer the managed reconciler adds to the
+	// resources it manages.
+	finalizer = "finalizer.managedresource.crossplane.io"
+
+	timeout = 30 * time.Second
+)
+
+// An integration is a controller manager that runs against a local API server
+// and a fake Console API.
+type integration struct {
+	kube    client.Client
+	console *fake.Server
+}
+
+// An integrationConfig configures the controller manager and fake Console API
+// of an integration.
+type integrationConfig struct {
+	// options configure all controllers.
+	options controller.Options
+
+	// intervals are the intervals managed resources are polled at.
+	intervals poll.Intervals
+
+	// restRate limits the requests per second to the API server, like
+	// --max-reconcile-rate does. The requests are not limited if it is zero.
+	restRate int
+
+	// console configures the fake Console API.
+	console []fake.Option
+}
+
+// defaultIntegrationConfig polls often, so that the integration tests
+// converge quickly.
+func defaultIntegrationConfig() integrationConfig {
+	return integrationConfig{
+		options: controller.Options{
+			Logger:                  logging.NewNopLogger(),
+			MaxConcurrentReconciles: 1,
+			PollInterval:            time.Second,
+			GlobalRateLimiter:       ratelimiter.NewGlobal(100),
+			Features:                &feature.Flags{},
+		},
+		intervals: poll.Intervals{Busy: 200 * time.Millisecond, Idle: time.Second},
+	}
+}
+
+// startIntegration starts a local API server with the provider's CRDs, and a
+// controller manager with all controllers. Both are stopped when the test
+// ends.
+func startIntegration(t *testing.T, c integrationConfig) *integration {
+	t.Helper()
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "package", "crds")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("env.Start(): %v", err)
+	}
+	t.Cleanup(func() { _ = env.Stop() })
+
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("clientgoscheme.AddToScheme(...): %v", err)
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatalf("apis.AddToScheme(...): %v", err)
+	}
+
+	if c.restRate > 0 {
+		cfg = ratelimiter.LimitRESTConfig(cfg, c.restRate)
+	}
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:                 s,
+		MetricsBindAddress:     "0",
+		HealthProbeBindAddress: "0",
+	})
+	if err != nil {
+		t.Fatalf("ctrl.NewManager(...): %v", err)
+	}
+	if err := Setup(mgr, c.options, c.intervals); err != nil {
+		t.Fatalf("Setup(...): %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- mgr.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("mgr.Start(...): %v", err)
+		}
+	})
+
+	srv := fake.NewServer(c.console...)
+	t.Cleanup(srv.Close)
+
+	i := &integration{kube: mgr.GetClient(), console: srv}
+	i.create(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: systemNamespace}})
+	i.create(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: systemNamespace, Name: "camunda-credentials"},
+		Data:       map[string][]byte{"credentials": srv.Credentials()},
+	})
+	i.create(t, &apisv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: apisv1beta1.ProviderConfigSpec{Credentials: apisv1beta1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Namespace: systemNamespace, Name: "camunda-credentials"},
+				Key:             "credentials",
+			}},
+		}},
+	})
+	return i
+}
+
+func (i *integration) create(t *testing.T, o client.Object) {
+	t.Helper()
+	if err := i.kube.Create(context.Background(), o); err != nil {
+		t.Fatalf("kube.Create(%s): %v", o.GetName(), err)
+	}
+}
+
+func (i *integration) delete(t *testing.T, o client.Object) {
+	t.Helper()
+	if err := i.kube.Delete(context.Background(), o); err != nil {
+		t.Fatalf("kube.Delete(%s): %v", o.GetName(), err)
+	}
+}
+
+// eventually fails the test unless the supplied condition holds within the
+// timeout. The condition is checked against a fresh copy of the supplied
+// object, and returns why it does not hold yet.
+func (i *integration) eventually(t *testing.T, o client.Object, reason string, cond func() error) {
+	t.Helper()
+	var last error
+	err := wait.PollImmediate(100*time.Millisecond, timeout, func() (bool, error) {
+		if err := i.kube.Get(context.Background(), types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, o); err != nil {
+			last = err
+		} else {
+			last = cond()
+		}
+		return last == nil, nil
+	})
+	if err != nil {
+		t.Fatalf("\n%s\n%s: %v", reason, o.GetName(), last)
+	}
+}
+
+// gone fails the test unless the supplied object is deleted within the
+// timeout.
+func (i *integration) gone(t *testing.T, o client.Object, reason string) {
+	t.Helper()
+	err := wait.PollImmediate(100*time.Millisecond, timeout, func() (bool, error) {
+		err := i.kube.Get(context.Background(), types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}, o)
+		return kerrors.IsNotFound(err), nil
+	})
+	if err != nil {
+		t.Fatalf("\n%s\n%s still exists, with finalizers %v", reason, o.GetName(), o.GetFinalizers())
+	}
+}
+
+// secret returns the data of the supplied connection secret.
+func (i *integration) secret(name string) (map[string][]byte, error) {
+	s := &corev1.Secret{}
+	if err := i.kube.Get(context.Background(), types.NamespacedName{Namespace: systemNamespace, Name: name}, s); err != nil {
+		return nil, err
+	}
+	return s.Data, nil
+}
+
+// ready returns an error unless the supplied resource is ready and synced.
+func ready(mg resource.Managed) error {
+	for _, ct := range []xpv1.ConditionType{xpv1.TypeReady, xpv1.TypeSynced} {
+		if c := mg.GetCondition(ct); c.Status != corev1.ConditionTrue {
+			return errors.Errorf("condition %s is %s: %s", ct, c.Status, c.Message)
+		}
+	}
+	return nil
+}
+
+// TestIntegration runs all integration tests against one API server and fake
+// Console API, since starting them takes a while.
+func TestIntegration(t *testing.T) {
+	i := startIntegration(t, defaultIntegrationConfig())
+
+	t.Run("ClusterAndClientLifecycle", i.testClusterAndClientLifecycle)
+	t.Run("ClusterDeletionProtection", i.testClusterDeletionProtection)
+	t.Run("ClusterCatalog", i.testClusterCatalog)
+	t.Run("TenantIsolation", i.testTenantIsolation)
+	t.Run("OrphanReport", i.testOrphanReport)
+}
+
+func (i *integration) testClusterAndClientLifecycle(t *testing.T) {
+	cl := &clusterv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+		Spec: clusterv1beta1.ClusterSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: systemNamespace, Name: "cool-cluster"},
+			},
+			ForProvider: clusterv1beta1.ClusterParameters{
+				Channel:    fake.ChannelID,
+				Generation: fake.GenerationID,
+				Region:     fake.RegionID,
+				PlanType:   fake.PlanTypeID,
+			},
+		},
+	}
+	i.create(t, cl)
+	i.eventually(t, cl, "A Cluster should become ready once its cluster is healthy.", func() error {
+		if err := ready(cl); err != nil {
+			return err
+		}
+		if !meta.FinalizerExists(cl, finalizer) {
+			return errors.New("finalizer was not added")
+		}
+		if _, ok := i.console.Cluster(meta.GetExternalName(cl)); !ok {
+			return errors.Errorf("external-name %q is not the ID of a cluster", meta.GetExternalName(cl))
+		}
+		d, err := i.secret("cool-cluster")
+		if err != nil {
+			return err
+		}
+		if len(d["zeebe"]) == 0 {
+			return errors.New("connection secret has no zeebe address")
+		}
+		return nil
+	})
+	clusterID := meta.GetExternalName(cl)
+
+	c := &clientv1beta1.Client{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+		Spec: clientv1beta1.ClientSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: systemNamespace, Name: "cool-client"},
+			},
+			ForProvider: clientv1beta1.ClientParameters{ClusterID: clusterID},
+		},
+	}
+	i.create(t, c)
+	i.eventually(t, c, "A Client should become ready and write its credentials.", func() error {
+		if err := ready(c); err != nil {
+			return err
+		}
+		clients := i.console.Clients(clusterID)
+		if len(clients) != 1 || clients[0].ClientId != meta.GetExternalName(c) {
+			return errors.Errorf("external-name %q is not the ID of the only client of the cluster", meta.GetExternalName(c))
+		}
+		d, err := i.secret("cool-client")
+		if err != nil {
+			return err
+		}
+		for _, k := range []string{"ZEEBE_CLIENT_ID", "ZEEBE_CLIENT_SECRET", "ZEEBE_ADDRESS", "ZEEBE_AUTHORIZATION_SERVER_URL"} {
+			if len(d[k]) == 0 {
+				return errors.Errorf("connection secret has no %s", k)
+			}
+		}
+		return nil
+	})
+
+	i.delete(t, cl)
+	i.eventually(t, cl, "A Cluster should not be deleted while a Client references it.", func() error {
+		if !meta.FinalizerExists(cl, finalizer) {
+			return errors.New("finalizer was removed")
+		}
+		if _, ok := i.console.Cluster(clusterID); !ok {
+			return errors.New("cluster was deleted")
+		}
+		want := fmt.Sprintf("cannot delete cluster while %d clients reference it", 1)
+		if s := cl.GetCondition(xpv1.TypeSynced); s.Status != corev1.ConditionFalse || !strings.Contains(s.Message, want) {
+			return errors.Errorf("condition %s is %s: %s", xpv1.TypeSynced, s.Status, s.Message)
+		}
+		return nil
+	})
+
+	i.delete(t, c)
+	i.gone(t, c, "A deleted Client should be removed once its client is deleted.")
+	if n := len(i.console.Clients(clusterID)); n != 0 {
+		t.Errorf("\nDeleting a Client should delete its client.\n%d clients are left", n)
+	}
+
+	i.gone(t, cl, "A deleted Cluster should be removed once no Client references it.")
+	if _, ok := i.console.Cluster(clusterID); ok {
+		t.Errorf("\nDeleting a Cluster should delete its cluster.\nThe cluster %s still exists", clusterID)
+	}
+}
+
+func (i *integration) testClusterDeletionProtection(t *testing.T) {
+	id := i.console.AddCluster(console.Cluster{
+		Name:       "protected-cluster",
+		Channel:    console.ClusterChannel{Uuid: fake.ChannelID},
+		Generation: console.ClusterGeneration{Uuid: fake.GenerationID},
+		Region:     console.ClusterRegion{Uuid: fake.RegionID},
+		PlanType:   console.ClusterPlanType{Uuid: fake.PlanTypeID},
+	})
+	cl := &clusterv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "protected-cluster",
+			Annotations: map[string]string{meta.AnnotationKeyExternalName: id},
+		},
+		Spec: clusterv1beta1.ClusterSpec{
+			ForProvider: clusterv1beta1.ClusterParameters{DeletionProtection: true},
+		},
+	}
+	i.create(t, cl)
+	i.eventually(t, cl, "An imported Cluster should be late-initialized from its cluster.", func() error {
+		if err := ready(cl); err != nil {
+			return err
+		}
+		if cl.Spec.ForProvider.Region != fake.RegionID {
+			return errors.Errorf("region is %q", cl.Spec.ForProvider.Region)
+		}
+		return nil
+	})
+
+	i.delete(t, cl)
+	i.eventually(t, cl, "A protected Cluster should not be deleted.", func() error {
+		if !meta.FinalizerExists(cl, finalizer) {
+			return errors.New("finalizer was removed")
+		}
+		if s := cl.GetCondition(xpv1.TypeSynced); s.Status != corev1.ConditionFalse {
+			return errors.Errorf("condition %s is %s", xpv1.TypeSynced, s.Status)
+		}
+		return nil
+	})
+	if _, ok := i.console.Cluster(id); !ok {
+		t.Errorf("\nA protected Cluster should not delete its cluster.\nThe cluster %s was deleted", id)
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := i.kube.Get(context.Background(), types.NamespacedName{Name: cl.GetName()}, cl); err != nil {
+			return err
+		}
+		cl.Spec.ForProvider.DeletionProtection = false
+		return i.kube.Update(context.Background(), cl)
+	})
+	if err != nil {
+		t.Fatalf("kube.Update(%s): %v", cl.GetName(), err)
+	}
+	i.gone(t, cl, "A Cluster should be deleted once its protection is lifted.")
+}
+
+func (i *integration) testClusterCatalog(t *testing.T) {
+	cc := &clusterv1beta1.ClusterCatalog{ObjectMeta: metav1.ObjectMeta{Name: "catalog"}}
+	i.create(t, cc)
+	i.eventually(t, cc, "A ClusterCatalog should list the cluster parameters of its ProviderConfig.", func() error {
+		if err := ready(cc); err != nil {
+			return err
+		}
+		o := cc.Status.AtProvider
+		if len(o.Channels) != 1 || o.Channels[0].ID != fake.ChannelID {
+			return errors.Errorf("channels are %v", o.Channels)
+		}
+		if d := o.Channels[0].DefaultGeneration; d == nil || d.ID != fake.GenerationID {
+			return errors.Errorf("default generation is %v", d)
+		}
+		if len(o.Regions) != 1 || len(o.PlanTypes) != 1 {
+			return errors.Errorf("regions are %v and plan types are %v", o.Regions, o.PlanTypes)
+		}
+		return nil
+	})
+
+	i.delete(t, cc)
+	i.gone(t, cc, "A deleted ClusterCatalog should be removed at once.")
+}
+
+func (i *integration) testTenantIsolation(t *testing.T) {
+	tenant := fake.NewServer(fake.WithOrganization("tenant-organization"))
+	t.Cleanup(tenant.Close)
+
+	ns := "tenant"
+	i.create(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	i.create(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "camunda-credentials"},
+		Data:       map[string][]byte{"credentials": tenant.Credentials()},
+	})
+	i.create(t, &namespacedv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "default"},
+		Spec: apisv1beta1.ProviderConfigSpec{Credentials: apisv1beta1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: "camunda-credentials"},
+				Key:             "credentials",
+			}},
+		}},
+	})
+
+	cl := &namespacedv1beta1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "tenant-cluster"},
+		Spec: namespacedv1beta1.ClusterSpec{ForProvider: clusterv1beta1.ClusterParameters{
+			Channel:    fake.ChannelID,
+			Generation: fake.GenerationID,
+			Region:     fake.RegionID,
+			PlanType:   fake.PlanTypeID,
+		}},
+	}
+	i.create(t, cl)
+	i.eventually(t, cl, "A namespaced Cluster should be created in the organization of its namespace's ProviderConfig.", func() error {
+		if err := ready(cl); err != nil {
+			return err
+		}
+		if _, ok := tenant.Cluster(meta.GetExternalName(cl)); !ok {
+			return errors.Errorf("external-name %q is not the ID of a cluster of the tenant", meta.GetExternalName(cl))
+		}
+		return nil
+	})
+	if _, ok := i.console.Cluster(meta.GetExternalName(cl)); ok {
+		t.Errorf("\nA namespaced Cluster should not be created in the organization of another ProviderConfig.\nThe cluster %s was created there", meta.GetExternalName(cl))
+	}
+
+	i.delete(t, cl)
+	i.gone(t, cl, "A deleted namespaced Cluster should be removed once its cluster is gone.")
+}
+
+func (i *integration) testOrphanReport(t *testing.T) {
+	id := i.console.AddCluster(console.Cluster{Name: "forgotten-cluster"})
+	or := &clusterv1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{Name: "orphans"}}
+	i.create(t, or)
+	i.eventually(t, or, "An OrphanReport should list the clusters no Cluster manages.", func() error {
+		if err := ready(or); err != nil {
+			return err
+		}
+		for _, cl := range or.Status.AtProvider.Clusters {
+			if cl.ID == id {
+				return nil
+			}
+		}
+		return errors.Errorf("unmanaged clusters are %v", or.Status.AtProvider.Clusters)
+	})
+	if _, ok := i.console.Cluster(id); !ok {
+		t.Fatalf("\nAn OrphanReport should not delete unmanaged clusters unless opted in.\nThe cluster %s was deleted", id)
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := i.kube.Get(context.Background(), types.NamespacedName{Name: or.GetName()}, or); err != nil {
+			return err
+		}
+		or.Spec.ForProvider.DeleteAfter = &metav1.Duration{Duration: time.Second}
+		return i.kube.Update(context.Background(), or)
+	})
+	if err != nil {
+		t.Fatalf("kube.Update(%s): %v", or.GetName(), err)
+	}
+	err = wait.PollImmediate(100*time.Millisecond, timeout, func() (bool, error) {
+		_, ok := i.console.Cluster(id)
+		return !ok, nil
+	})
+	if err != nil {
+		t.Fatalf("\nAn OrphanReport should delete unmanaged clusters after deleteAfter.\nThe cluster %s still exists", id)
+	}
+
+	i.delete(t, or)
+	i.gone(t, or, "A deleted OrphanReport should be removed at once.")
+}
@@ -24,4 +24,9 @@ const (
 	// External Secret Stores. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
 	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
+
+	// EnableCatalogValidation enables checking the parameters of Clusters
+	// against the parameters catalog of the Console API when they are
+	// admitted.
+	EnableCatalogValidation feature.Flag = "EnableCatalogValidation"
 )
@@ -0,0 +1,412 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package orphan reports the clusters and clients of an organization that no
+// managed resource manages.
+package orphan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	apisv1alpha1 "github.com/crossplane/provider-camunda/apis/v1alpha1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/controller/config"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+	"github.com/crossplane/provider-camunda/internal/metrics"
+	"github.com/crossplane/provider-camunda/internal/tracing"
+)
+
+const (
+	errNotMyType    = "managed resource is not an OrphanReport custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+
+	errNewClient = "cannot create new Service"
+
+	errListManaged   = "cannot list managed resources"
+	errListClusters  = "cannot list clusters"
+	errListClients   = "cannot list clients"
+	errDeleteCluster = "cannot delete unmanaged cluster"
+	errDeleteClient  = "cannot delete unmanaged client"
+)
+
+// Event reasons.
+const (
+	reasonUnmanagedCluster        event.Reason = "UnmanagedCluster"
+	reasonUnmanagedClient         event.Reason = "UnmanagedClient"
+	reasonDeletedUnmanagedCluster event.Reason = "DeletedUnmanagedCluster"
+	reasonDeletedUnmanagedClient  event.Reason = "DeletedUnmanagedClient"
+)
+
+// Kinds of unmanaged resources, used as metric labels.
+const (
+	kindCluster = "cluster"
+	kindClient  = "client"
+)
+
+// Setup adds a controller that reconciles OrphanReports. An OrphanReport is
+// refreshed at the idle interval, since unmanaged resources are not urgent.
+func Setup(mgr ctrl.Manager, o controller.Options, pi poll.Intervals) error {
+	name := managed.ControllerName(v1beta1.OrphanReportGroupKind)
+	record := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1beta1.OrphanReportGroupVersionKind),
+		managed.WithExternalConnecter(tracing.NewConnecter(v1beta1.OrphanReportKind, &connector{
+			kube:         mgr.GetClient(),
+			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			record:       record,
+			newServiceFn: camunda.NewService,
+			cacheTTL:     pi.Busy})),
+		// An OrphanReport does not correspond to an external resource, so it
+		// has no external-name.
+		managed.WithInitializers(),
+		managed.WithPollInterval(pi.Idle),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(record))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.OrphanReport{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	record       event.Recorder
+	newServiceFn func(ctx context.Context, key string, creds []byte) (camunda.API, error)
+	cacheTTL     time.Duration
+}
+
+// Connect produces an ExternalClient that compares the clusters and clients
+// the credentials of the OrphanReport's ProviderConfig grant access to with
+// the managed resources.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.OrphanReport); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc, err := config.Resolve(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	data, err := pc.Credentials(ctx, c.kube)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(ctx, pc.Key, data)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{
+		service: svc,
+		cache:   camunda.ObservationCache(pc.Key, data, c.cacheTTL),
+		kube:    c.kube,
+		record:  c.record,
+		now:     time.Now,
+	}, nil
+}
+
+// An external reports the clusters and clients no managed resource manages.
+// It updates the report by deleting those that are due for deletion, and
+// invalidates the observation cache the Cluster and Client controllers share
+// with it for each.
+type external struct {
+	service camunda.API
+	cache   *camunda.Cache
+	kube    client.Client
+	record  event.Recorder
+	now     func() time.Time
+}
+
+// Observe writes the unmanaged clusters and clients to the status of the
+// OrphanReport. The report always exists. It is up to date unless unmanaged
+// resources are due for deletion.
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.OrphanReport)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	// Nothing needs to be deleted, so the finalizer can be removed at once.
+	if meta.WasDeleted(cr) {
+		metrics.DeleteReport(cr.GetName())
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	m, err := e.managedIDs(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListManaged)
+	}
+
+	clusters, err := e.service.ListClusters(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListClusters)
+	}
+
+	now := metav1.NewTime(e.now())
+	last := cr.GetObservation()
+	o := v1beta1.OrphanReportObservation{}
+	for _, cl := range clusters {
+		if !m.clusters[cl.ID] {
+			u := v1beta1.UnmanagedCluster{ID: cl.ID, Name: cl.Name, FirstSeen: now}
+			if prev := findCluster(last.Clusters, cl.ID); prev != nil {
+				u.FirstSeen = prev.FirstSeen
+			} else {
+				e.record.Event(cr, event.Event{Type: event.TypeWarning, Reason: reasonUnmanagedCluster,
+					Message: fmt.Sprintf("Cluster %q (%s) is not managed by any Cluster", cl.Name, cl.ID)})
+			}
+			o.Clusters = append(o.Clusters, u)
+			continue
+		}
+
+		// The clients of unmanaged clusters go with them, so only the
+		// clients of managed clusters are checked.
+		clients, err := e.service.ListClients(ctx, cl.ID)
+		if camunda.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errListClients)
+		}
+		for _, c := range clients {
+			if m.clients[c.ID] {
+				continue
+			}
+			u := v1beta1.UnmanagedClient{ID: c.ID, Name: c.Name, ClusterID: cl.ID, FirstSeen: now}
+			if prev := findClient(last.Clients, c.ID); prev != nil {
+				u.FirstSeen = prev.FirstSeen
+			} else {
+				e.record.Event(cr, event.Event{Type: event.TypeWarning, Reason: reasonUnmanagedClient,
+					Message: fmt.Sprintf("Client %q (%s) of cluster %s is not managed by any Client", c.Name, c.ID, cl.ID)})
+			}
+			o.Clients = append(o.Clients, u)
+		}
+	}
+
+	o.DeletionsHeldBack = cr.GetParameters().DeleteAfter != nil && m.incomplete
+	*last = o
+	metrics.UnmanagedResources.WithLabelValues(cr.GetName(), kindCluster).Set(float64(len(o.Clusters)))
+	metrics.UnmanagedResources.WithLabelValues(cr.GetName(), kindClient).Set(float64(len(o.Clients)))
+	cr.SetConditions(xpv1.Available())
+
+	clustersDue, clientsDue := due(cr, e.now())
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(clustersDue) == 0 && len(clientsDue) == 0,
+	}, nil
+}
+
+// Create is never called, since the report always exists.
+func (e *external) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, nil
+}
+
+// Update deletes the unmanaged clusters and clients that are due for deletion
+// and removes them from the report.
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.OrphanReport)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	clustersDue, clientsDue := due(cr, e.now())
+	o := cr.GetObservation()
+	for _, cl := range clustersDue {
+		if err := e.service.DeleteCluster(ctx, cl.ID); resource.Ignore(camunda.IsNotFound, err) != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errDeleteCluster)
+		}
+		// The cached list may still hold the cluster, even if it was already
+		// gone.
+		e.cache.InvalidateClusters()
+		e.record.Event(cr, event.Normal(reasonDeletedUnmanagedCluster, fmt.Sprintf("Deleted unmanaged cluster %q (%s)", cl.Name, cl.ID)))
+		o.Clusters = removeCluster(o.Clusters, cl.ID)
+	}
+	for _, c := range clientsDue {
+		if err := e.service.DeleteClient(ctx, c.ClusterID, c.ID); resource.Ignore(camunda.IsNotFound, err) != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errDeleteClient)
+		}
+		// The cached list may still hold the client, even if it was already
+		// gone.
+		e.cache.InvalidateClients(c.ClusterID)
+		e.record.Event(cr, event.Normal(reasonDeletedUnmanagedClient, fmt.Sprintf("Deleted unmanaged client %q (%s) of cluster %s", c.Name, c.ID, c.ClusterID)))
+		o.Clients = removeClient(o.Clients, c.ID)
+	}
+	metrics.UnmanagedResources.WithLabelValues(cr.GetName(), kindCluster).Set(float64(len(o.Clusters)))
+	metrics.UnmanagedResources.WithLabelValues(cr.GetName(), kindClient).Set(float64(len(o.Clients)))
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete does nothing, since deleting the report leaves the Console alone.
+func (e *external) Delete(_ context.Context, _ resource.Managed) error {
+	return nil
+}
+
+// A clientResource is a Client managed resource of either scope.
+type clientResource interface {
+	resource.Managed
+	GetParameters() *clientv1beta1.ClientParameters
+}
+
+// managedIDs are the IDs of the clusters and clients managed resources manage.
+type managedIDs struct {
+	clusters map[string]bool
+	clients  map[string]bool
+
+	// incomplete is true if a managed resource has not recorded the ID of
+	// its external resource, so that its external resource is not among
+	// the managed IDs.
+	incomplete bool
+}
+
+// managedIDs returns the external-names of all Clusters and Clients of either
+// scope, regardless of their ProviderConfig. A cluster that Clients reference
+// counts as managed too, so that it is not deleted from under them.
+func (e *external) managedIDs(ctx context.Context) (managedIDs, error) {
+	m := managedIDs{clusters: map[string]bool{}, clients: map[string]bool{}}
+	for _, l := range []resource.ManagedList{&v1beta1.ClusterList{}, &namespacedv1beta1.ClusterList{}} {
+		if err := e.kube.List(ctx, l); err != nil {
+			return managedIDs{}, err
+		}
+		for _, mg := range l.GetItems() {
+			m.incomplete = m.incomplete || incomplete(mg)
+			if id := meta.GetExternalName(mg); id != "" {
+				m.clusters[id] = true
+			}
+		}
+	}
+
+	for _, l := range []resource.ManagedList{&clientv1beta1.ClientList{}, &namespacedv1beta1.ClientList{}} {
+		if err := e.kube.List(ctx, l); err != nil {
+			return managedIDs{}, err
+		}
+		for _, mg := range l.GetItems() {
+			m.incomplete = m.incomplete || incomplete(mg)
+			if id := meta.GetExternalName(mg); id != "" {
+				m.clients[id] = true
+			}
+			if c, ok := mg.(clientResource); ok && c.GetParameters().ClusterID != "" {
+				m.clusters[c.GetParameters().ClusterID] = true
+			}
+		}
+	}
+	return m, nil
+}
+
+// incomplete returns true if the supplied managed resource has not recorded
+// the ID of its external resource yet. The create pending annotation is not
+// removed once a creation succeeded, so it only counts while the creation is
+// incomplete.
+func incomplete(mg resource.Managed) bool {
+	return meta.GetExternalName(mg) == "" || meta.ExternalCreateIncomplete(mg)
+}
+
+// due returns the unmanaged clusters and clients of the supplied report that
+// have been reported for at least its deleteAfter duration at the supplied
+// time. Nothing is due unless deleteAfter is set, or while deletions are held
+// back. A deleteAfter below the minimum counts as the minimum, for API servers
+// that do not enforce it.
+func due(cr *v1beta1.OrphanReport, now time.Time) ([]v1beta1.UnmanagedCluster, []v1beta1.UnmanagedClient) {
+	if cr.GetParameters().DeleteAfter == nil || cr.GetObservation().DeletionsHeldBack {
+		return nil, nil
+	}
+	after := cr.GetParameters().DeleteAfter.Duration
+	if after < v1beta1.MinDeleteAfter {
+		after = v1beta1.MinDeleteAfter
+	}
+	var clusters []v1beta1.UnmanagedCluster
+	for _, cl := range cr.GetObservation().Clusters {
+		if !cl.FirstSeen.Add(after).After(now) {
+			clusters = append(clusters, cl)
+		}
+	}
+	var clients []v1beta1.UnmanagedClient
+	for _, c := range cr.GetObservation().Clients {
+		if !c.FirstSeen.Add(after).After(now) {
+			clients = append(clients, c)
+		}
+	}
+	return clusters, clients
+}
+
+func findCluster(l []v1beta1.UnmanagedCluster, id string) *v1beta1.UnmanagedCluster {
+	for i := range l {
+		if l[i].ID == id {
+			return &l[i]
+		}
+	}
+	return nil
+}
+
+func findClient(l []v1beta1.UnmanagedClient, id string) *v1beta1.UnmanagedClient {
+	for i := range l {
+		if l[i].ID == id {
+			return &l[i]
+		}
+	}
+	return nil
+}
+
+func removeCluster(l []v1beta1.UnmanagedCluster, id string) []v1beta1.UnmanagedCluster {
+	out := make([]v1beta1.UnmanagedCluster, 0, len(l))
+	for _, cl := range l {
+		if cl.ID != id {
+			out = append(out, cl)
+		}
+	}
+	return out
+}
+
+func removeClient(l []v1beta1.UnmanagedClient, id string) []v1beta1.UnmanagedClient {
+	out := make([]v1beta1.UnmanagedClient, 0, len(l))
+	for _, c := range l {
+		if c.ID != id {
+			out = append(out, c)
+		}
+	}
+	return out
+}
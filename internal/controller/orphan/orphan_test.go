@@ -0,0 +1,618 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	console "github.com/sijoma/console-customer-api-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	apisv1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+const (
+	managedCluster    = "9a8b7c6d-0000-4000-8000-000000000001"
+	unmanagedCluster  = "9a8b7c6d-0000-4000-8000-000000000002"
+	referencedCluster = "9a8b7c6d-0000-4000-8000-000000000003"
+
+	managedClient   = "managed-client"
+	unmanagedClient = "unmanaged-client"
+	foreignClient   = "foreign-client"
+)
+
+// managedResources lists a Cluster managing managedCluster, a Client managing
+// managedClient and a namespaced Client of referencedCluster, whose Cluster
+// is not managed.
+func managedResources(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	switch l := list.(type) {
+	case *v1beta1.ClusterList:
+		l.Items = []v1beta1.Cluster{{}}
+		meta.SetExternalName(&l.Items[0], managedCluster)
+	case *clientv1beta1.ClientList:
+		l.Items = []clientv1beta1.Client{{Spec: clientv1beta1.ClientSpec{ForProvider: clientv1beta1.ClientParameters{ClusterID: managedCluster}}}}
+		meta.SetExternalName(&l.Items[0], managedClient)
+	case *namespacedv1beta1.ClientList:
+		l.Items = []namespacedv1beta1.Client{{Spec: namespacedv1beta1.ClientSpec{ForProvider: clientv1beta1.ClientParameters{ClusterID: referencedCluster}}}}
+		meta.SetExternalName(&l.Items[0], "referencing-client")
+	}
+	return nil
+}
+
+// creating lists the managed resources, and a namespaced Cluster that is
+// being created and has not recorded the ID of its cluster yet.
+func creating(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if l, ok := list.(*namespacedv1beta1.ClusterList); ok {
+		l.Items = []namespacedv1beta1.Cluster{{}}
+		return nil
+	}
+	return managedResources(ctx, list, opts...)
+}
+
+// createPending lists the managed resources, with the managed Client's
+// creation still pending.
+func createPending(now time.Time) test.MockListFn {
+	return func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		if err := managedResources(ctx, list, opts...); err != nil {
+			return err
+		}
+		if l, ok := list.(*clientv1beta1.ClientList); ok {
+			meta.SetExternalCreatePending(&l.Items[0], now)
+		}
+		return nil
+	}
+}
+
+func TestConnect(t *testing.T) {
+	// Each organization has a cluster created by hand.
+	clusters := map[string]string{"acme": unmanagedCluster, "initech": referencedCluster}
+	consoles := map[string]*fake.Server{}
+	for org, id := range clusters {
+		consoles[org] = fake.NewServer(fake.WithOrganization(org), fake.WithClusters(console.Cluster{Uuid: id, Name: org}))
+	}
+	for _, srv := range consoles {
+		defer srv.Close()
+	}
+
+	// Each ProviderConfig reads the credentials of the organization it is
+	// named after from a Secret of the same name.
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *apisv1beta1.ProviderConfig:
+				o.Spec.Credentials = apisv1beta1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: key.Name},
+						Key:             "credentials",
+					}},
+				}
+			case *corev1.Secret:
+				o.Data = map[string][]byte{"credentials": consoles[key.Name].Credentials()}
+			}
+			return nil
+		},
+		MockList: test.NewMockListFn(nil),
+	}
+	c := &connector{
+		kube:         kube,
+		usage:        resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+		record:       event.NewNopRecorder(),
+		newServiceFn: camunda.NewService,
+	}
+
+	for _, pc := range []string{"acme", "initech", "acme"} {
+		cr := &v1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{Name: "report"}}
+		cr.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+		e, err := c.Connect(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("c.Connect(%s): unexpected error: %v", pc, err)
+		}
+		if _, err := e.Observe(context.Background(), cr); err != nil {
+			t.Fatalf("e.Observe(%s): unexpected error: %v", pc, err)
+		}
+		var got []string
+		for _, u := range cr.Status.AtProvider.Clusters {
+			got = append(got, u.ID)
+		}
+		if diff := cmp.Diff([]string{clusters[pc]}, got); diff != "" {
+			t.Errorf("\nAn OrphanReport should list the clusters of its own ProviderConfig's organization.\ne.Observe(%s): -want, +got:\n%s\n", pc, diff)
+		}
+	}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	earlier := metav1.NewTime(now.Add(-2 * time.Hour))
+	deleted := metav1.NewTime(now)
+
+	clusters := []camunda.Cluster{
+		{ID: managedCluster, ClusterSpec: camunda.ClusterSpec{Name: "managed"}},
+		{ID: unmanagedCluster, ClusterSpec: camunda.ClusterSpec{Name: "by-hand"}},
+		{ID: referencedCluster, ClusterSpec: camunda.ClusterSpec{Name: "referenced"}},
+	}
+	clients := map[string][]camunda.Client{
+		managedCluster:    {{ID: managedClient, Name: "worker"}, {ID: unmanagedClient, Name: "debug"}},
+		referencedCluster: {{ID: foreignClient, Name: "by-hand"}},
+	}
+	reported := v1beta1.OrphanReportObservation{
+		Clusters: []v1beta1.UnmanagedCluster{{ID: unmanagedCluster, Name: "by-hand", FirstSeen: earlier}},
+		Clients: []v1beta1.UnmanagedClient{
+			{ID: unmanagedClient, Name: "debug", ClusterID: managedCluster, FirstSeen: metav1.NewTime(now)},
+			{ID: foreignClient, Name: "by-hand", ClusterID: referencedCluster, FirstSeen: metav1.NewTime(now)},
+		},
+	}
+
+	heldBack := reported
+	heldBack.DeletionsHeldBack = true
+
+	type want struct {
+		cr  *v1beta1.OrphanReport
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		err    error
+		cr     *v1beta1.OrphanReport
+		want   want
+	}{
+		"Reported": {
+			reason: "Unmanaged clusters and the unmanaged clients of managed clusters should be reported, keeping when they were first seen.",
+			kube:   &test.MockClient{MockList: managedResources},
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+					Clusters: []v1beta1.UnmanagedCluster{{ID: unmanagedCluster, Name: "by-hand", FirstSeen: earlier}},
+				}},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Status: v1beta1.OrphanReportStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider:     reported,
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"NotDue": {
+			reason: "A report should be up to date while no unmanaged resource was reported for deleteAfter.",
+			kube:   &test.MockClient{MockList: managedResources},
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: 3 * time.Hour}}},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: 3 * time.Hour}}},
+					Status: v1beta1.OrphanReportStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider: v1beta1.OrphanReportObservation{
+							Clusters: []v1beta1.UnmanagedCluster{{ID: unmanagedCluster, Name: "by-hand", FirstSeen: metav1.NewTime(now)}},
+							Clients:  reported.Clients,
+						},
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Due": {
+			reason: "A report should need an update once an unmanaged resource was reported for deleteAfter.",
+			kube:   &test.MockClient{MockList: managedResources},
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+					Clusters: []v1beta1.UnmanagedCluster{{ID: unmanagedCluster, Name: "by-hand", FirstSeen: earlier}},
+				}},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+					Status: v1beta1.OrphanReportStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider:     reported,
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"HeldBackWhileCreating": {
+			reason: "Deletions should be held back while a Cluster has not recorded the ID of its cluster.",
+			kube:   &test.MockClient{MockList: creating},
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+					Clusters: []v1beta1.UnmanagedCluster{{ID: unmanagedCluster, Name: "by-hand", FirstSeen: earlier}},
+				}},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+					Status: v1beta1.OrphanReportStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider:     heldBack,
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"HeldBackWhileCreatePending": {
+			reason: "Deletions should be held back while the creation of a Client is pending.",
+			kube:   &test.MockClient{MockList: createPending(now)},
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+					Clusters: []v1beta1.UnmanagedCluster{{ID: unmanagedCluster, Name: "by-hand", FirstSeen: earlier}},
+				}},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+					Status: v1beta1.OrphanReportStatus{
+						ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+						AtProvider:     heldBack,
+					},
+				},
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"ListManagedError": {
+			reason: "Errors listing the managed resources should be returned, so that nothing is reported from partial knowledge.",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			cr:     &v1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{Name: "report"}},
+			want: want{
+				cr:  &v1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{Name: "report"}},
+				err: errors.Wrap(errBoom, errListManaged),
+			},
+		},
+		"ListClustersError": {
+			reason: "Errors listing the clusters should be returned.",
+			kube:   &test.MockClient{MockList: managedResources},
+			err:    errBoom,
+			cr:     &v1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{Name: "report"}},
+			want: want{
+				cr:  &v1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{Name: "report"}},
+				err: errors.Wrap(errBoom, errListClusters),
+			},
+		},
+		"Deleted": {
+			reason: "A deleted OrphanReport should be reported as gone, since there is nothing to delete.",
+			cr:     &v1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted}},
+			want: want{
+				cr: &v1beta1.OrphanReport{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted}},
+				o:  managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				service: &fake.MockAPI{
+					MockListClusters: func(_ context.Context) ([]camunda.Cluster, error) { return clusters, tc.err },
+					MockListClients: func(_ context.Context, clusterID string) ([]camunda.Client, error) {
+						return clients[clusterID], nil
+					},
+				},
+				kube:   tc.kube,
+				record: event.NewNopRecorder(),
+				now:    func() time.Time { return now },
+			}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	due := metav1.NewTime(now.Add(-2 * time.Hour))
+	recent := metav1.NewTime(now.Add(-time.Minute))
+
+	observed := v1beta1.OrphanReportObservation{
+		Clusters: []v1beta1.UnmanagedCluster{
+			{ID: unmanagedCluster, Name: "by-hand", FirstSeen: due},
+			{ID: referencedCluster, Name: "new", FirstSeen: recent},
+		},
+		Clients: []v1beta1.UnmanagedClient{{ID: unmanagedClient, Name: "debug", ClusterID: managedCluster, FirstSeen: due}},
+	}
+	heldBack := observed
+	heldBack.DeletionsHeldBack = true
+
+	type want struct {
+		cr       *v1beta1.OrphanReport
+		clusters []string
+		clients  []string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		cr     *v1beta1.OrphanReport
+		want   want
+	}{
+		"DeletedDue": {
+			reason: "Unmanaged resources reported for deleteAfter should be deleted and removed from the report.",
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status:     v1beta1.OrphanReportStatus{AtProvider: observed},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+					Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+						Clusters: []v1beta1.UnmanagedCluster{{ID: referencedCluster, Name: "new", FirstSeen: recent}},
+						Clients:  []v1beta1.UnmanagedClient{},
+					}},
+				},
+				clusters: []string{unmanagedCluster},
+				clients:  []string{unmanagedClient},
+			},
+		},
+		"BelowMinimum": {
+			reason: "A deleteAfter below the minimum should count as the minimum.",
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Second}}},
+				Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+					Clusters: []v1beta1.UnmanagedCluster{{ID: referencedCluster, Name: "new", FirstSeen: recent}},
+				}},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Second}}},
+					Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+						Clusters: []v1beta1.UnmanagedCluster{{ID: referencedCluster, Name: "new", FirstSeen: recent}},
+					}},
+				},
+			},
+		},
+		"HeldBack": {
+			reason: "Nothing should be deleted while deletions are held back.",
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status:     v1beta1.OrphanReportStatus{AtProvider: heldBack},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+					Status:     v1beta1.OrphanReportStatus{AtProvider: heldBack},
+				},
+			},
+		},
+		"NotOptedIn": {
+			reason: "Nothing should be deleted unless deleteAfter is set.",
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Status:     v1beta1.OrphanReportStatus{AtProvider: observed},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Status:     v1beta1.OrphanReportStatus{AtProvider: observed},
+				},
+			},
+		},
+		"AlreadyGone": {
+			reason: "Unmanaged resources that are already gone should be removed from the report.",
+			err:    camunda.NotFoundError{Err: errBoom},
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status:     v1beta1.OrphanReportStatus{AtProvider: observed},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+					Status: v1beta1.OrphanReportStatus{AtProvider: v1beta1.OrphanReportObservation{
+						Clusters: []v1beta1.UnmanagedCluster{{ID: referencedCluster, Name: "new", FirstSeen: recent}},
+						Clients:  []v1beta1.UnmanagedClient{},
+					}},
+				},
+				clusters: []string{unmanagedCluster},
+				clients:  []string{unmanagedClient},
+			},
+		},
+		"DeleteClusterError": {
+			reason: "Errors deleting an unmanaged cluster should be returned.",
+			err:    errBoom,
+			cr: &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status:     v1beta1.OrphanReportStatus{AtProvider: observed},
+			},
+			want: want{
+				cr: &v1beta1.OrphanReport{
+					ObjectMeta: metav1.ObjectMeta{Name: "report"},
+					Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+					Status:     v1beta1.OrphanReportStatus{AtProvider: observed},
+				},
+				clusters: []string{unmanagedCluster},
+				err:      errors.Wrap(errBoom, errDeleteCluster),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var clusters, clients []string
+			e := &external{
+				service: &fake.MockAPI{
+					MockDeleteCluster: func(_ context.Context, id string) error {
+						clusters = append(clusters, id)
+						return tc.err
+					},
+					MockDeleteClient: func(_ context.Context, _, id string) error {
+						clients = append(clients, id)
+						return tc.err
+					},
+				},
+				cache:  camunda.NewCache(0),
+				record: event.NewNopRecorder(),
+				now:    func() time.Time { return now },
+			}
+			_, err := e.Update(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.clusters, clusters); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want deleted clusters, +got deleted clusters:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.clients, clients); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want deleted clients, +got deleted clients:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateInvalidatesCache(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	due := metav1.NewTime(now.Add(-2 * time.Hour))
+
+	type want struct {
+		clusterLists int
+		clientLists  int
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		o      v1beta1.OrphanReportObservation
+		want   want
+	}{
+		"DeletedCluster": {
+			reason: "The cached clusters should be invalidated once an unmanaged cluster was deleted.",
+			o:      v1beta1.OrphanReportObservation{Clusters: []v1beta1.UnmanagedCluster{{ID: unmanagedCluster, FirstSeen: due}}},
+			want:   want{clusterLists: 2, clientLists: 2},
+		},
+		"DeletedClient": {
+			reason: "The cached clients of its cluster should be invalidated once an unmanaged client was deleted.",
+			o:      v1beta1.OrphanReportObservation{Clients: []v1beta1.UnmanagedClient{{ID: unmanagedClient, ClusterID: managedCluster, FirstSeen: due}}},
+			want:   want{clusterLists: 1, clientLists: 2},
+		},
+		"AlreadyGone": {
+			reason: "The cached clients should be invalidated if the unmanaged client was already gone.",
+			err:    camunda.NotFoundError{Err: errors.New("404 Not Found")},
+			o:      v1beta1.OrphanReportObservation{Clients: []v1beta1.UnmanagedClient{{ID: unmanagedClient, ClusterID: managedCluster, FirstSeen: due}}},
+			want:   want{clusterLists: 1, clientLists: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			api := &fake.MockAPI{
+				MockListClusters: func(_ context.Context) ([]camunda.Cluster, error) {
+					got.clusterLists++
+					return []camunda.Cluster{{ID: managedCluster}, {ID: unmanagedCluster}}, nil
+				},
+				MockListClients: func(_ context.Context, _ string) ([]camunda.Client, error) {
+					got.clientLists++
+					return []camunda.Client{{ID: managedClient}, {ID: unmanagedClient}}, nil
+				},
+				MockGetClient: func(_ context.Context, _, _ string) (*camunda.ClientDetails, error) {
+					return &camunda.ClientDetails{}, nil
+				},
+				MockDeleteCluster: func(_ context.Context, _ string) error { return tc.err },
+				MockDeleteClient:  func(_ context.Context, _, _ string) error { return tc.err },
+			}
+			e := &external{service: api, cache: camunda.NewCache(time.Hour), record: event.NewNopRecorder(), now: func() time.Time { return now }}
+
+			// Observe the managed resources through the cache, before and
+			// after the update.
+			ctx := context.Background()
+			observe := func() {
+				if _, _, err := e.cache.GetCluster(ctx, api, managedCluster); err != nil {
+					t.Fatalf("e.cache.GetCluster(...): unexpected error: %v", err)
+				}
+				if _, _, err := e.cache.GetClient(ctx, api, managedCluster, managedClient); err != nil {
+					t.Fatalf("e.cache.GetClient(...): unexpected error: %v", err)
+				}
+			}
+			observe()
+			cr := &v1beta1.OrphanReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec:       v1beta1.OrphanReportSpec{ForProvider: v1beta1.OrphanReportParameters{DeleteAfter: &metav1.Duration{Duration: time.Hour}}},
+				Status:     v1beta1.OrphanReportStatus{AtProvider: tc.o},
+			}
+			if _, err := e.Update(ctx, cr); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			observe()
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want lists, +got lists:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package poll adapts how often managed resources are polled to their
+// observed state.
+package poll
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Intervals at which managed resources are polled, depending on their state.
+type Intervals struct {
+	// Busy is the poll interval of resources that are being created, updated
+	// or deleted.
+	Busy time.Duration
+
+	// Idle is the poll interval of resources that are healthy and idle.
+	Idle time.Duration
+}
+
+// An IntervalHook returns how long to wait before the supplied managed
+// resource is polled again. The supplied poll interval is the default.
+type IntervalHook func(mg resource.Managed, pollInterval time.Duration) time.Duration
+
+// A Reconciler wraps a managed resource reconciler and replaces the poll
+// interval it requeues with by the one returned by an IntervalHook.
+type Reconciler struct {
+	inner      reconcile.Reconciler
+	kube       client.Reader
+	newManaged func() resource.Managed
+	hook       IntervalHook
+}
+
+// NewReconciler returns a Reconciler that wraps the supplied reconciler of
+// managed resources created by the supplied function.
+func NewReconciler(r reconcile.Reconciler, kube client.Reader, newManaged func() resource.Managed, h IntervalHook) *Reconciler {
+	return &Reconciler{inner: r, kube: kube, newManaged: newManaged, hook: h}
+}
+
+// Reconcile the supplied request using the wrapped reconciler. Requests that
+// are requeued after the poll interval are requeued after the interval
+// returned by the hook instead.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	res, err := r.inner.Reconcile(ctx, req)
+	if err != nil || res.RequeueAfter == 0 {
+		return res, err
+	}
+
+	mg := r.newManaged()
+	if err := r.kube.Get(ctx, req.NamespacedName, mg); err != nil {
+		// Keep the default poll interval if we can't tell the state of the
+		// managed resource.
+		return res, nil
+	}
+	if d := r.hook(mg, res.RequeueAfter); d > 0 {
+		res.RequeueAfter = d
+	}
+	return res, nil
+}
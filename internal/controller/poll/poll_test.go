@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type reconcilerFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcilerFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	result := func(res reconcile.Result, err error) reconcile.Reconciler {
+		return reconcilerFn(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) { return res, err })
+	}
+	hook := func(_ resource.Managed, _ time.Duration) time.Duration { return 10 * time.Second }
+	noHook := func(_ resource.Managed, _ time.Duration) time.Duration { return 0 }
+
+	type fields struct {
+		inner reconcile.Reconciler
+		kube  client.Reader
+		hook  IntervalHook
+	}
+
+	type want struct {
+		res reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		want   want
+	}{
+		"Error": {
+			reason: "Errors of the wrapped reconciler should be returned as is.",
+			fields: fields{
+				inner: result(reconcile.Result{Requeue: true}, errBoom),
+				hook:  hook,
+			},
+			want: want{res: reconcile.Result{Requeue: true}, err: errBoom},
+		},
+		"ImmediateRequeue": {
+			reason: "Immediate requeues should not be delayed.",
+			fields: fields{
+				inner: result(reconcile.Result{Requeue: true}, nil),
+				hook:  hook,
+			},
+			want: want{res: reconcile.Result{Requeue: true}},
+		},
+		"GetError": {
+			reason: "The default poll interval should be kept if the managed resource can't be read.",
+			fields: fields{
+				inner: result(reconcile.Result{RequeueAfter: time.Minute}, nil),
+				kube:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				hook:  hook,
+			},
+			want: want{res: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"NoInterval": {
+			reason: "The default poll interval should be kept if the hook returns no interval.",
+			fields: fields{
+				inner: result(reconcile.Result{RequeueAfter: time.Minute}, nil),
+				kube:  &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				hook:  noHook,
+			},
+			want: want{res: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"Interval": {
+			reason: "The poll interval returned by the hook should be used.",
+			fields: fields{
+				inner: result(reconcile.Result{RequeueAfter: time.Minute}, nil),
+				kube:  &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				hook:  hook,
+			},
+			want: want{res: reconcile.Result{RequeueAfter: 10 * time.Second}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.fields.inner, tc.fields.kube, func() resource.Managed { return &fake.Managed{} }, tc.fields.hook)
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
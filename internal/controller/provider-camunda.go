@@ -21,19 +21,27 @@ import (
 	"github.com/crossplane/provider-camunda/internal/controller/client"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/crossplane/provider-camunda/internal/controller/catalog"
 	"github.com/crossplane/provider-camunda/internal/controller/cluster"
 	"github.com/crossplane/provider-camunda/internal/controller/config"
+	"github.com/crossplane/provider-camunda/internal/controller/orphan"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
 )
 
 // Setup creates Camunda controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options) error{
-		config.Setup,
+// the supplied manager. Managed resources are polled at the supplied intervals
+// depending on their state.
+func Setup(mgr ctrl.Manager, o controller.Options, pi poll.Intervals) error {
+	if err := config.Setup(mgr, o); err != nil {
+		return err
+	}
+	for _, setup := range []func(ctrl.Manager, controller.Options, poll.Intervals) error{
 		cluster.Setup,
 		client.Setup,
+		catalog.Setup,
+		orphan.Setup,
 	} {
-		if err := setup(mgr, o); err != nil {
+		if err := setup(mgr, o, pi); err != nil {
 			return err
 		}
 	}
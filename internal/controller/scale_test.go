@@ -0,0 +1,410 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	clientv1beta1 "github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	clusterv1beta1 "github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda/fake"
+	"github.com/crossplane/provider-camunda/internal/controller/poll"
+)
+
+// The scale harness runs all controllers against a local API server and a
+// fake Console API with many Clusters and Clients, and reports how much load
+// they put on the Console API and how quickly resources become ready. Like
+// the integration tests it needs KUBEBUILDER_ASSETS. Run it on its own with:
+//
+//	go test ./internal/controller/ -run TestScale -scale -timeout 30m
+var (
+	scale             = flag.Bool("scale", false, "Run the scale harness instead of the integration tests.")
+	scaleClusters     = flag.Int("scale.clusters", 10, "The number of Clusters to create.")
+	scaleClients      = flag.Int("scale.clients", 100, "The number of Clients to create, spread evenly across the Clusters.")
+	scaleRate         = flag.Int("scale.max-reconcile-rate", 10, "The provider's --max-reconcile-rate.")
+	scalePoll         = flag.Duration("scale.poll", time.Minute, "The provider's --poll.")
+	scalePollBusy     = flag.Duration("scale.poll-busy", 10*time.Second, "The provider's --poll-busy.")
+	scalePollIdle     = flag.Duration("scale.poll-idle", 10*time.Minute, "The provider's --poll-idle.")
+	scaleProvisioning = flag.Duration("scale.provisioning", 10*time.Second, "How long new clusters are Creating before they become Healthy.")
+	scaleSteady       = flag.Duration("scale.steady", 2*time.Minute, "How long to count Console API calls for once all resources are ready.")
+	scaleReady        = flag.Duration("scale.ready-timeout", 15*time.Minute, "How long to wait for all resources to become ready.")
+	scaleReportFile   = flag.String("scale.report", "", "A file to write the report to as JSON, to compare runs.")
+)
+
+// A scaleReport is the result of a run of the scale harness. Durations are in
+// seconds.
+type scaleReport struct {
+	Clusters         int    `json:"clusters"`
+	Clients          int    `json:"clients"`
+	MaxReconcileRate int    `json:"maxReconcileRate"`
+	Poll             string `json:"poll"`
+	PollBusy         string `json:"pollBusy"`
+	PollIdle         string `json:"pollIdle"`
+	Provisioning     string `json:"provisioning"`
+
+	// ClusterTimeToReady is how long Clusters took from their creation until
+	// they were ready.
+	ClusterTimeToReady percentiles `json:"clusterTimeToReady"`
+
+	// ClientTimeToReady is how long Clients took from their creation until
+	// they were ready.
+	ClientTimeToReady percentiles `json:"clientTimeToReady"`
+
+	// ReconcileLatency is how long reconciles took, by controller. They are
+	// estimated from controller-runtime's reconcile time histogram.
+	ReconcileLatency map[string]percentiles `json:"reconcileLatency"`
+
+	// RampCallsPerMinute are the Console API calls per minute by endpoint
+	// while the resources became ready.
+	RampCallsPerMinute map[string]float64 `json:"rampCallsPerMinute"`
+
+	// SteadyCallsPerMinute are the Console API calls per minute by endpoint
+	// once all resources were ready.
+	SteadyCallsPerMinute map[string]float64 `json:"steadyCallsPerMinute"`
+}
+
+// percentiles of a distribution of durations, in seconds.
+type percentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+func TestScale(t *testing.T) {
+	if !*scale {
+		t.Skip("Run with -scale to run the scale harness")
+	}
+	i := startIntegration(t, integrationConfig{
+		options: controller.Options{
+			Logger:                  logging.NewNopLogger(),
+			MaxConcurrentReconciles: *scaleRate,
+			PollInterval:            *scalePoll,
+			GlobalRateLimiter:       ratelimiter.NewGlobal(*scaleRate),
+			Features:                &feature.Flags{},
+		},
+		intervals: poll.Intervals{Busy: *scalePollBusy, Idle: *scalePollIdle},
+		restRate:  *scaleRate,
+		console:   []fake.Option{fake.WithProvisioning(*scaleProvisioning)},
+	})
+
+	r := &scaleReport{
+		Clusters:         *scaleClusters,
+		Clients:          *scaleClients,
+		MaxReconcileRate: *scaleRate,
+		Poll:             scalePoll.String(),
+		PollBusy:         scalePollBusy.String(),
+		PollIdle:         scalePollIdle.String(),
+		Provisioning:     scaleProvisioning.String(),
+	}
+
+	start, before := time.Now(), i.console.AllRequests()
+	created := map[string]time.Time{}
+	for n := 0; n < *scaleClusters; n++ {
+		cl := &clusterv1beta1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("scale-cluster-%04d", n)},
+			Spec: clusterv1beta1.ClusterSpec{ForProvider: clusterv1beta1.ClusterParameters{
+				Channel:    fake.ChannelID,
+				Generation: fake.GenerationID,
+				Region:     fake.RegionID,
+				PlanType:   fake.PlanTypeID,
+			}},
+		}
+		i.create(t, cl)
+		created["Cluster/"+cl.GetName()] = time.Now()
+	}
+	for n := 0; n < *scaleClients && *scaleClusters > 0; n++ {
+		c := &clientv1beta1.Client{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("scale-client-%04d", n)},
+			Spec: clientv1beta1.ClientSpec{ForProvider: clientv1beta1.ClientParameters{
+				ClusterIDRef: &xpv1.Reference{Name: fmt.Sprintf("scale-cluster-%04d", n%*scaleClusters)},
+			}},
+		}
+		i.create(t, c)
+		created["Client/"+c.GetName()] = time.Now()
+	}
+
+	clusters, clients := i.waitReady(t, created)
+	ramp := time.Since(start)
+	r.ClusterTimeToReady = samplePercentiles(clusters)
+	r.ClientTimeToReady = samplePercentiles(clients)
+	r.RampCallsPerMinute = callsPerMinute(before, i.console.AllRequests(), ramp)
+
+	before = i.console.AllRequests()
+	time.Sleep(*scaleSteady)
+	r.SteadyCallsPerMinute = callsPerMinute(before, i.console.AllRequests(), *scaleSteady)
+
+	lat, err := reconcileLatency()
+	if err != nil {
+		t.Fatalf("reconcileLatency(): %v", err)
+	}
+	r.ReconcileLatency = lat
+
+	t.Log("\n" + r.String())
+	if *scaleReportFile != "" {
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(*scaleReportFile, append(b, '\n'), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// waitReady waits until all supplied resources are ready, and returns how long
+// each Cluster and Client took from its supplied creation time.
+func (i *integration) waitReady(t *testing.T, created map[string]time.Time) (clusters, clients []time.Duration) {
+	t.Helper()
+	ready := map[string]bool{}
+	observe := func(kind string, l []resource.Managed) {
+		for _, mg := range l {
+			key := kind + "/" + mg.GetName()
+			c, ok := created[key]
+			if !ok || ready[key] || mg.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+				continue
+			}
+			ready[key] = true
+			if kind == "Cluster" {
+				clusters = append(clusters, time.Since(c))
+				continue
+			}
+			clients = append(clients, time.Since(c))
+		}
+	}
+
+	deadline := time.Now().Add(*scaleReady)
+	for len(ready) < len(created) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Only %d of %d resources became ready within %s", len(ready), len(created), *scaleReady)
+		}
+		cl := &clusterv1beta1.ClusterList{}
+		if err := i.kube.List(context.Background(), cl); err != nil {
+			t.Fatalf("kube.List(...): %v", err)
+		}
+		observe("Cluster", cl.GetItems())
+		c := &clientv1beta1.ClientList{}
+		if err := i.kube.List(context.Background(), c); err != nil {
+			t.Fatalf("kube.List(...): %v", err)
+		}
+		observe("Client", c.GetItems())
+		time.Sleep(250 * time.Millisecond)
+	}
+	return clusters, clients
+}
+
+// callsPerMinute returns the rate of calls per endpoint between the supplied
+// request counts.
+func callsPerMinute(before, after map[string]int, d time.Duration) map[string]float64 {
+	rates := map[string]float64{}
+	for e, n := range after {
+		if calls := n - before[e]; calls > 0 {
+			rates[e] = float64(calls) / d.Minutes()
+		}
+	}
+	return rates
+}
+
+// samplePercentiles returns the percentiles of the supplied durations.
+func samplePercentiles(d []time.Duration) percentiles {
+	if len(d) == 0 {
+		return percentiles{}
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	at := func(q float64) float64 {
+		return d[int(math.Ceil(q*float64(len(d))))-1].Seconds()
+	}
+	return percentiles{P50: at(0.5), P90: at(0.9), P99: at(0.99), Max: d[len(d)-1].Seconds()}
+}
+
+// reconcileLatency estimates the percentiles of reconcile times by controller
+// from controller-runtime's reconcile time histogram. The maximum is the upper
+// bound of the highest bucket with samples.
+func reconcileLatency() (map[string]percentiles, error) {
+	mfs, err := ctrlmetrics.Registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+	lat := map[string]percentiles{}
+	for _, mf := range mfs {
+		if mf.GetName() != "controller_runtime_reconcile_time_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			h := m.GetHistogram()
+			if h.GetSampleCount() == 0 {
+				continue
+			}
+			lat[label(m, "controller")] = percentiles{
+				P50: histogramQuantile(0.5, h),
+				P90: histogramQuantile(0.9, h),
+				P99: histogramQuantile(0.99, h),
+				Max: histogramQuantile(1, h),
+			}
+		}
+	}
+	return lat, nil
+}
+
+func label(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+// histogramQuantile estimates the supplied quantile of a histogram by linear
+// interpolation within the bucket it falls into, like PromQL does.
+func histogramQuantile(q float64, h *dto.Histogram) float64 {
+	rank := q * float64(h.GetSampleCount())
+	lower, below := 0.0, 0.0
+	for _, b := range h.GetBucket() {
+		n := float64(b.GetCumulativeCount())
+		if n >= rank {
+			if n == below {
+				return lower
+			}
+			return lower + (b.GetUpperBound()-lower)*(rank-below)/(n-below)
+		}
+		lower, below = b.GetUpperBound(), n
+	}
+	return lower
+}
+
+func (r *scaleReport) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%d Clusters and %d Clients with --max-reconcile-rate=%d --poll=%s --poll-busy=%s --poll-idle=%s, provisioned in %s\n\n",
+		r.Clusters, r.Clients, r.MaxReconcileRate, r.Poll, r.PollBusy, r.PollIdle, r.Provisioning)
+
+	w := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+	row := func(name string, p percentiles) {
+		fmt.Fprintf(w, "%s\t%.3fs\t%.3fs\t%.3fs\t%.3fs\n", name, p.P50, p.P90, p.P99, p.Max)
+	}
+	fmt.Fprintln(w, "TIME TO READY\tP50\tP90\tP99\tMAX")
+	row("Cluster", r.ClusterTimeToReady)
+	row("Client", r.ClientTimeToReady)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "RECONCILE LATENCY\tP50\tP90\tP99\tMAX")
+	for _, c := range sortedKeys(r.ReconcileLatency) {
+		row(c, r.ReconcileLatency[c])
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "CONSOLE API CALLS PER MINUTE\tRAMP\tSTEADY")
+	endpoints := map[string]bool{}
+	for e := range r.RampCallsPerMinute {
+		endpoints[e] = true
+	}
+	for e := range r.SteadyCallsPerMinute {
+		endpoints[e] = true
+	}
+	var ramp, steady float64
+	for _, e := range sortedKeys(endpoints) {
+		fmt.Fprintf(w, "%s\t%.1f\t%.1f\n", e, r.RampCallsPerMinute[e], r.SteadyCallsPerMinute[e])
+		ramp += r.RampCallsPerMinute[e]
+		steady += r.SteadyCallsPerMinute[e]
+	}
+	fmt.Fprintf(w, "Total\t%.1f\t%.1f\n", ramp, steady)
+	_ = w.Flush()
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	bucket := func(upper float64, n uint64) *dto.Bucket {
+		return &dto.Bucket{UpperBound: &upper, CumulativeCount: &n}
+	}
+	count := uint64(10)
+	h := &dto.Histogram{SampleCount: &count, Bucket: []*dto.Bucket{bucket(0.1, 5), bucket(0.5, 5), bucket(1, 10)}}
+
+	cases := map[string]struct {
+		reason string
+		q      float64
+		want   float64
+	}{
+		"FirstBucket": {
+			reason: "A quantile in the first bucket should be interpolated from zero.",
+			q:      0.25,
+			want:   0.05,
+		},
+		"UpperBucket": {
+			reason: "A quantile should be interpolated within the bucket it falls into.",
+			q:      0.9,
+			want:   0.9,
+		},
+		"Max": {
+			reason: "The maximum should be the upper bound of the highest bucket with samples.",
+			q:      1,
+			want:   1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := histogramQuantile(tc.q, h)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("\n%s\nhistogramQuantile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSamplePercentiles(t *testing.T) {
+	d := make([]time.Duration, 100)
+	for i := range d {
+		d[i] = time.Duration(100-i) * time.Second
+	}
+	want := percentiles{P50: 50, P90: 90, P99: 99, Max: 100}
+	if diff := cmp.Diff(want, samplePercentiles(d)); diff != "" {
+		t.Errorf("\nPercentiles should be the nearest ranked samples.\nsamplePercentiles(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains the Prometheus metrics of the provider. They are
+// registered with controller-runtime's metrics registry and thus served by
+// the manager's metrics endpoint.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Results of a token refresh, used as metric labels.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// statusClassError is the status class of requests that did not receive a
+// response.
+const statusClassError = "error"
+
+var (
+	// ConsoleRequestDuration is the latency of Console API requests.
+	ConsoleRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "camunda_console_api_request_duration_seconds",
+		Help:    "Latency of Console API requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint", "status_class"})
+
+	// ConsoleErrors counts Console API requests that failed or were answered
+	// with an error status.
+	ConsoleErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "camunda_console_api_errors_total",
+		Help: "Number of Console API requests that failed or were answered with an error status.",
+	}, []string{"endpoint", "status_class"})
+
+	// TokenRefreshes counts exchanges of credentials for access tokens.
+	TokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "camunda_token_refreshes_total",
+		Help: "Number of exchanges of Console API credentials for access tokens.",
+	}, []string{"result"})
+
+	// ClusterComponentHealthy is 1 if a component of a cluster is healthy and
+	// 0 otherwise.
+	ClusterComponentHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "camunda_cluster_component_healthy",
+		Help: "Whether a component of a Camunda cluster is healthy (1) or not (0).",
+	}, []string{"cluster", "component"})
+
+	// ObservationCacheHits counts observations served from the observation
+	// cache.
+	ObservationCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "camunda_observation_cache_hits_total",
+		Help: "Number of observations served from the observation cache.",
+	}, []string{"kind"})
+
+	// ObservationCacheMisses counts observations that required a Console API
+	// call.
+	ObservationCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "camunda_observation_cache_misses_total",
+		Help: "Number of observations that required a Console API call.",
+	}, []string{"kind"})
+
+	// UnmanagedResources is the number of clusters and clients an OrphanReport
+	// found not to be managed by any managed resource.
+	UnmanagedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "camunda_unmanaged_resources",
+		Help: "Number of Camunda clusters and clients that no managed resource manages.",
+	}, []string{"report", "kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ConsoleRequestDuration,
+		ConsoleErrors,
+		TokenRefreshes,
+		ClusterComponentHealthy,
+		ObservationCacheHits,
+		ObservationCacheMisses,
+		UnmanagedResources,
+	)
+}
+
+// SetClusterComponentHealthy records whether the supplied component of the
+// supplied cluster is healthy.
+func SetClusterComponentHealthy(cluster, component string, healthy bool) {
+	v := 0.0
+	if healthy {
+		v = 1
+	}
+	ClusterComponentHealthy.WithLabelValues(cluster, component).Set(v)
+}
+
+// DeleteCluster drops all metrics of the supplied cluster, for example because
+// it was deleted.
+func DeleteCluster(cluster string) {
+	ClusterComponentHealthy.DeletePartialMatch(prometheus.Labels{"cluster": cluster})
+}
+
+// DeleteReport drops all metrics of the supplied OrphanReport, for example
+// because it was deleted.
+func DeleteReport(report string) {
+	UnmanagedResources.DeletePartialMatch(prometheus.Labels{"report": report})
+}
+
+// NewTransport returns an http.RoundTripper that records the latency and errors
+// of Console API requests before passing them to the supplied RoundTripper.
+func NewTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next, now: time.Now}
+}
+
+type transport struct {
+	next http.RoundTripper
+	now  func() time.Time
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.now()
+	resp, err := t.next.RoundTrip(req)
+
+	ep := Endpoint(req)
+	sc := statusClassError
+	if err == nil {
+		sc = StatusClass(resp.StatusCode)
+	}
+	ConsoleRequestDuration.WithLabelValues(ep, sc).Observe(t.now().Sub(start).Seconds())
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		ConsoleErrors.WithLabelValues(ep, sc).Inc()
+	}
+	return resp, err
+}
+
+// collections are the path segments of the Console API that are followed by
+// the ID of one of their members.
+var collections = map[string]bool{
+	"clusters": true,
+	"clients":  true,
+	"secrets":  true,
+	"members":  true,
+}
+
+// literals are the path segments that follow a collection but are not IDs.
+var literals = map[string]bool{
+	"parameters": true,
+}
+
+// Endpoint returns the method and path of the supplied request with IDs
+// replaced by placeholders, for example "GET /clusters/{id}/clients", so that
+// it can be used as a metric label of bounded cardinality.
+func Endpoint(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if collections[segments[i-1]] && !literals[segments[i]] {
+			segments[i] = "{id}"
+		}
+	}
+	return req.Method + " /" + strings.Join(segments, "/")
+}
+
+// StatusClass returns the class of the supplied HTTP status code, for example
+// "2xx".
+func StatusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
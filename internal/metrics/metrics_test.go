@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestEndpoint(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		method string
+		path   string
+		want   string
+	}{
+		"Collection": {
+			reason: "A collection should be returned as is.",
+			method: http.MethodGet,
+			path:   "/clusters",
+			want:   "GET /clusters",
+		},
+		"Member": {
+			reason: "The ID of a member of a collection should be replaced.",
+			method: http.MethodDelete,
+			path:   "/clusters/8a3f7c1e-5d2b-4f6a-9c0e-1b2d3e4f5a6b",
+			want:   "DELETE /clusters/{id}",
+		},
+		"Nested": {
+			reason: "The IDs of nested members should be replaced.",
+			method: http.MethodGet,
+			path:   "/clusters/8a3f7c1e-5d2b-4f6a-9c0e-1b2d3e4f5a6b/clients/my-client",
+			want:   "GET /clusters/{id}/clients/{id}",
+		},
+		"Literal": {
+			reason: "Literal path segments following a collection should not be replaced.",
+			method: http.MethodGet,
+			path:   "/clusters/parameters",
+			want:   "GET /clusters/parameters",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Endpoint(httptest.NewRequest(tc.method, tc.path, nil))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEndpoint(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	cases := map[string]struct {
+		code int
+		want string
+	}{
+		"OK":       {code: http.StatusOK, want: "2xx"},
+		"NotFound": {code: http.StatusNotFound, want: "4xx"},
+		"Internal": {code: http.StatusBadGateway, want: "5xx"},
+		"Invalid":  {code: 42, want: "unknown"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, StatusClass(tc.code)); diff != "" {
+				t.Errorf("\nStatusClass(%d): -want, +got:\n%s\n", tc.code, diff)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/clusters/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: NewTransport(nil)}
+	for _, path := range []string{"/clusters", "/clusters/missing"} {
+		resp, err := c.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("c.Get(%q): %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	cases := map[string]struct {
+		reason string
+		got    float64
+		want   float64
+	}{
+		"ErrorStatus": {
+			reason: "A request answered with an error status should be counted as an error.",
+			got:    testutil.ToFloat64(ConsoleErrors.WithLabelValues("GET /clusters/{id}", "4xx")),
+			want:   1,
+		},
+		"SuccessStatus": {
+			reason: "A successful request should not be counted as an error.",
+			got:    testutil.ToFloat64(ConsoleErrors.WithLabelValues("GET /clusters", "2xx")),
+			want:   0,
+		},
+		"Latency": {
+			reason: "The latency of every request should be observed.",
+			got:    float64(testutil.CollectAndCount(ConsoleRequestDuration)),
+			want:   2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+				t.Errorf("\n%s\nRoundTrip(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
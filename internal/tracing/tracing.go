@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing traces reconciles of managed resources and the Console API
+// calls they make with OpenTelemetry.
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	xpresource "github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	tracerName  = "github.com/crossplane/provider-camunda"
+	serviceName = "provider-camunda"
+
+	errNewExporter = "cannot create OTLP trace exporter"
+)
+
+// Attributes of the spans of managed resource operations.
+const (
+	AttributeKind         = attribute.Key("crossplane.kind")
+	AttributeName         = attribute.Key("crossplane.name")
+	AttributeExternalName = attribute.Key("crossplane.external_name")
+
+	// AttributeNamespace is only set for namespaced managed resources.
+	AttributeNamespace = semconv.K8SNamespaceNameKey
+)
+
+// Options configure the export of traces.
+type Options struct {
+	// Endpoint is the host and port of the OTLP/HTTP collector traces are
+	// exported to. Traces are not exported when it is empty.
+	Endpoint string
+
+	// Insecure disables TLS when connecting to the collector.
+	Insecure bool
+
+	// SampleRatio is the fraction of traces that are sampled.
+	SampleRatio float64
+}
+
+// Setup installs a global tracer provider that exports traces according to the
+// supplied options. It leaves the default no-op tracer provider in place if no
+// endpoint is configured. The returned function flushes and stops the export.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	if o.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	eo := []otlptracehttp.Option{otlptracehttp.WithEndpoint(o.Endpoint)}
+	if o.Insecure {
+		eo = append(eo, otlptracehttp.WithInsecure())
+	}
+	exp, err := otlptracehttp.New(ctx, eo...)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewExporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp.Shutdown, nil
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// start starts a span of the supplied operation on the supplied managed
+// resource.
+func start(ctx context.Context, kind, op string, mg xpresource.Managed) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		AttributeKind.String(kind),
+		AttributeName.String(mg.GetName()),
+		AttributeExternalName.String(meta.GetExternalName(mg)),
+	}
+	if ns := mg.GetNamespace(); ns != "" {
+		attrs = append(attrs, AttributeNamespace.String(ns))
+	}
+	return tracer().Start(ctx, kind+"."+op, trace.WithAttributes(attrs...))
+}
+
+// end records the supplied error, if any, and ends the supplied span.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// NewConnecter returns a managed.ExternalConnecter that traces the Connect
+// calls of the supplied ExternalConnecter and the calls of the ExternalClients
+// it produces. Spans are named after the supplied kind, for example
+// "Cluster.Observe".
+func NewConnecter(kind string, c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &connecter{kind: kind, wrapped: c}
+}
+
+type connecter struct {
+	kind    string
+	wrapped managed.ExternalConnecter
+}
+
+func (c *connecter) Connect(ctx context.Context, mg xpresource.Managed) (managed.ExternalClient, error) {
+	ctx, span := start(ctx, c.kind, "Connect", mg)
+	ec, err := c.wrapped.Connect(ctx, mg)
+	end(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kind: c.kind, wrapped: ec}, nil
+}
+
+type external struct {
+	kind    string
+	wrapped managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg xpresource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := start(ctx, e.kind, "Observe", mg)
+	o, err := e.wrapped.Observe(ctx, mg)
+	span.SetAttributes(
+		attribute.Bool("crossplane.resource_exists", o.ResourceExists),
+		attribute.Bool("crossplane.resource_up_to_date", o.ResourceUpToDate),
+	)
+	end(span, err)
+	return o, err
+}
+
+func (e *external) Create(ctx context.Context, mg xpresource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := start(ctx, e.kind, "Create", mg)
+	c, err := e.wrapped.Create(ctx, mg)
+	// The external-name is usually only known once the resource was created.
+	span.SetAttributes(AttributeExternalName.String(meta.GetExternalName(mg)))
+	end(span, err)
+	return c, err
+}
+
+func (e *external) Update(ctx context.Context, mg xpresource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := start(ctx, e.kind, "Update", mg)
+	u, err := e.wrapped.Update(ctx, mg)
+	end(span, err)
+	return u, err
+}
+
+func (e *external) Delete(ctx context.Context, mg xpresource.Managed) error {
+	ctx, span := start(ctx, e.kind, "Delete", mg)
+	err := e.wrapped.Delete(ctx, mg)
+	end(span, err)
+	return err
+}
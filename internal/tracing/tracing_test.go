@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type span struct {
+	Name       string
+	Attributes map[attribute.Key]string
+	Code       codes.Code
+}
+
+func TestConnecter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		err   error
+		spans []span
+	}
+
+	cases := map[string]struct {
+		reason    string
+		namespace string
+		c         managed.ExternalConnecter
+		want      want
+	}{
+		"ConnectError": {
+			reason: "A failed Connect should be recorded as an error span.",
+			c: managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				err: errBoom,
+				spans: []span{
+					{Name: "Test.Connect", Attributes: map[attribute.Key]string{AttributeKind: "Test", AttributeName: "cool", AttributeExternalName: "cool-id"}, Code: codes.Error},
+				},
+			},
+		},
+		"Observe": {
+			reason: "Connect and Observe should each be recorded as a span.",
+			c: managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+				return &managed.ExternalClientFns{
+					ObserveFn: func(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+						return managed.ExternalObservation{ResourceExists: true}, nil
+					},
+				}, nil
+			}),
+			want: want{
+				spans: []span{
+					{Name: "Test.Connect", Attributes: map[attribute.Key]string{AttributeKind: "Test", AttributeName: "cool", AttributeExternalName: "cool-id"}},
+					{Name: "Test.Observe", Attributes: map[attribute.Key]string{AttributeKind: "Test", AttributeName: "cool", AttributeExternalName: "cool-id"}},
+				},
+			},
+		},
+		"Namespaced": {
+			reason:    "Spans of namespaced resources should record their namespace.",
+			namespace: "default",
+			c: managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				err: errBoom,
+				spans: []span{
+					{Name: "Test.Connect", Attributes: map[attribute.Key]string{AttributeKind: "Test", AttributeName: "cool", AttributeExternalName: "cool-id", AttributeNamespace: "default"}, Code: codes.Error},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+			mg := &fake.Managed{}
+			mg.SetName("cool")
+			mg.SetNamespace(tc.namespace)
+			meta.SetExternalName(mg, "cool-id")
+
+			ec, err := NewConnecter("Test", tc.c).Connect(context.Background(), mg)
+			if err == nil {
+				_, err = ec.Observe(context.Background(), mg)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConnect(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+
+			got := []span{}
+			for _, s := range sr.Ended() {
+				attrs := map[attribute.Key]string{}
+				for _, kv := range s.Attributes() {
+					if kv.Value.Type() == attribute.STRING {
+						attrs[kv.Key] = kv.Value.AsString()
+					}
+				}
+				got = append(got, span{Name: s.Name(), Attributes: attrs, Code: s.Status().Code})
+			}
+			if diff := cmp.Diff(tc.want.spans, got); diff != "" {
+				t.Errorf("\n%s\nConnect(...): -want spans, +got spans:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
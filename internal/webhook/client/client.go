@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client contains the admission webhook of Client managed resources.
+package client
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+)
+
+const (
+	errNotClient = "object is not a client custom resource"
+
+	errImmutable = "field is immutable once set"
+	errNotUUID   = "must be a UUID"
+
+	errMissingCluster = "either clusterID, clusterIDRef or clusterIDSelector is required"
+
+	errPublishConnectionDetails = "namespaced managed resources cannot publish connection details to a cluster scoped StoreConfig, use writeConnectionSecretToRef"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-camunda-crossplane-io-v1beta1-client,mutating=false,failurePolicy=fail,groups=camunda.crossplane.io,resources=clients,versions=v1beta1,matchPolicy=Equivalent,name=clients.camunda.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-camunda-m-crossplane-io-v1beta1-client,mutating=false,failurePolicy=fail,groups=camunda.m.crossplane.io,resources=clients,versions=v1beta1,name=clients.camunda.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// A clientResource is a Client managed resource of either scope.
+type clientResource interface {
+	resource.Managed
+	GetParameters() *v1beta1.ClientParameters
+}
+
+// Setup adds validating webhooks for cluster scoped and namespaced Client
+// managed resources.
+func Setup(mgr ctrl.Manager, _ controller.Options) error {
+	for _, obj := range []runtime.Object{&v1beta1.Client{}, &namespacedv1beta1.Client{}} {
+		if err := ctrl.NewWebhookManagedBy(mgr).
+			For(obj).
+			WithValidator(&validator{}).
+			Complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A validator validates Client managed resources.
+type validator struct{}
+
+// ValidateCreate rejects Clients without a valid cluster ID or a reference to
+// their Cluster.
+func (v *validator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	cr, ok := obj.(clientResource)
+	if !ok {
+		return errors.New(errNotClient)
+	}
+	return invalid(cr, append(validate(cr), validateNamespaced(cr)...))
+}
+
+// ValidateUpdate additionally rejects changes to the cluster ID of a Client,
+// since a client cannot be moved to another cluster.
+func (v *validator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	cr, ok := newObj.(clientResource)
+	if !ok {
+		return errors.New(errNotClient)
+	}
+	old, ok := oldObj.(clientResource)
+	if !ok {
+		return errors.New(errNotClient)
+	}
+	errs := append(validate(cr), validateNamespaced(cr)...)
+	if id := old.GetParameters().ClusterID; id != "" && cr.GetParameters().ClusterID != id {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "forProvider", "clusterID"), cr.GetParameters().ClusterID, errImmutable))
+	}
+	return invalid(cr, errs)
+}
+
+// ValidateDelete accepts all Client deletions.
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+// validate returns the problems with the parameters of the supplied Client.
+func validate(cr clientResource) field.ErrorList {
+	fp := cr.GetParameters()
+	p := field.NewPath("spec", "forProvider", "clusterID")
+	id := fp.ClusterID
+	if id == "" {
+		if fp.ClusterIDRef != nil || fp.ClusterIDSelector != nil {
+			return nil
+		}
+		return field.ErrorList{field.Required(p, errMissingCluster)}
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return field.ErrorList{field.Invalid(p, id, errNotUUID)}
+	}
+	return nil
+}
+
+// validateNamespaced returns the problems with the fields of the supplied
+// Client that a namespaced Client does not support.
+func validateNamespaced(cr clientResource) field.ErrorList {
+	if n, ok := cr.(*namespacedv1beta1.Client); ok && n.Spec.PublishConnectionDetailsTo != nil {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "publishConnectionDetailsTo"), errPublishConnectionDetails)}
+	}
+	return nil
+}
+
+// invalid returns an Invalid API error for the supplied Client if there are
+// any problems.
+func invalid(cr clientResource, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	gk := v1beta1.ClientGroupVersionKind.GroupKind()
+	if _, ok := cr.(*namespacedv1beta1.Client); ok {
+		gk = namespacedv1beta1.ClientGroupVersionKind.GroupKind()
+	}
+	return kerrors.NewInvalid(gk, cr.GetName(), errs)
+}
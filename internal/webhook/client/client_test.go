@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-camunda/apis/client/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+)
+
+const (
+	clusterID = "6bdcf0e5-ff1e-4f7c-a5a3-0b6a1e7f2b10"
+	otherID   = "ffffffff-ffff-4fff-bfff-ffffffffffff"
+)
+
+func client(clusterID string) *v1beta1.Client {
+	return &v1beta1.Client{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+		Spec:       v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{ClusterID: clusterID}},
+	}
+}
+
+func invalidClient(errs ...*field.Error) error {
+	return kerrors.NewInvalid(v1beta1.ClientGroupVersionKind.GroupKind(), "cool-client", errs)
+}
+
+func TestValidateCreate(t *testing.T) {
+	p := field.NewPath("spec", "forProvider", "clusterID")
+
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		want   error
+	}{
+		"NotClient": {
+			reason: "An error should be returned if the object is not a Client.",
+			obj:    &v1beta1.ClientList{},
+			want:   errors.New(errNotClient),
+		},
+		"Valid": {
+			reason: "A Client with a cluster ID should be accepted.",
+			obj:    client(clusterID),
+		},
+		"MissingClusterID": {
+			reason: "A Client without a cluster ID should be rejected.",
+			obj:    client(""),
+			want:   invalidClient(field.Required(p, errMissingCluster)),
+		},
+		"ClusterReference": {
+			reason: "A Client that references its Cluster does not need a cluster ID.",
+			obj: &v1beta1.Client{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-client"},
+				Spec: v1beta1.ClientSpec{ForProvider: v1beta1.ClientParameters{
+					ClusterIDRef: &xpv1.Reference{Name: "cool-cluster"},
+				}},
+			},
+		},
+		"NotUUID": {
+			reason: "A Client whose cluster ID is not a UUID should be rejected.",
+			obj:    client("my-cluster"),
+			want:   invalidClient(field.Invalid(p, "my-cluster", errNotUUID)),
+		},
+		"NamespacedMissingClusterID": {
+			reason: "A namespaced Client without a cluster ID should be rejected as a namespaced Client.",
+			obj: &namespacedv1beta1.Client{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cool-client"},
+			},
+			want: kerrors.NewInvalid(namespacedv1beta1.ClientGroupVersionKind.GroupKind(), "cool-client", field.ErrorList{field.Required(p, errMissingCluster)}),
+		},
+		"NamespacedPublishConnectionDetails": {
+			reason: "A namespaced Client should not publish connection details to a cluster scoped StoreConfig.",
+			obj: &namespacedv1beta1.Client{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cool-client"},
+				Spec: namespacedv1beta1.ClientSpec{
+					ResourceSpec: namespacedv1beta1.ResourceSpec{
+						PublishConnectionDetailsTo: &xpv1.PublishConnectionDetailsTo{Name: "cool-client"},
+					},
+					ForProvider: v1beta1.ClientParameters{ClusterID: clusterID},
+				},
+			},
+			want: kerrors.NewInvalid(namespacedv1beta1.ClientGroupVersionKind.GroupKind(), "cool-client", field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "publishConnectionDetailsTo"), errPublishConnectionDetails),
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{}
+			err := v.ValidateCreate(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateCreate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	type args struct {
+		oldObj runtime.Object
+		newObj runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Unchanged": {
+			reason: "An update that does not change the cluster ID should be accepted.",
+			args:   args{oldObj: client(clusterID), newObj: client(clusterID)},
+		},
+		"ClusterIDChanged": {
+			reason: "Changing the cluster ID of a Client should be rejected.",
+			args:   args{oldObj: client(clusterID), newObj: client(otherID)},
+			want:   invalidClient(field.Invalid(field.NewPath("spec", "forProvider", "clusterID"), otherID, errImmutable)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{}
+			err := v.ValidateUpdate(context.Background(), tc.args.oldObj, tc.args.newObj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateUpdate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+	"github.com/crossplane/provider-camunda/internal/controller/config"
+	"github.com/crossplane/provider-camunda/internal/controller/features"
+)
+
+const (
+	errNotCluster        = "object is not a cluster custom resource"
+	errDeletionProtected = "cluster is protected from deletion, remove spec.forProvider.deletionProtection and the " + v1beta1.AnnotationKeyDeletionProtection + " annotation first"
+	errGetPC             = "cannot get ProviderConfig"
+	errGetCreds          = "cannot get credentials"
+	errNewService        = "cannot create new Service"
+	errGetParameters     = "cannot get cluster parameters"
+
+	errImmutable         = "field is immutable once set"
+	errNotUUID           = "must be a UUID"
+	errUnknownChannel    = "unknown release channel"
+	errUnknownGeneration = "unknown generation or not allowed for the release channel"
+	errUnknownRegion     = "unknown region"
+	errUnknownPlanType   = "unknown plan type"
+
+	errPublishConnectionDetails = "namespaced managed resources cannot publish connection details to a cluster scoped StoreConfig, use writeConnectionSecretToRef"
+)
+
+// +kubebuilder:webhook:verbs=create;update;delete,path=/validate-camunda-crossplane-io-v1beta1-cluster,mutating=false,failurePolicy=fail,groups=camunda.crossplane.io,resources=clusters,versions=v1beta1,matchPolicy=Equivalent,name=clusters.camunda.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// +kubebuilder:webhook:verbs=create,path=/mutate-camunda-crossplane-io-v1beta1-cluster,mutating=true,failurePolicy=fail,groups=camunda.crossplane.io,resources=clusters,versions=v1beta1,matchPolicy=Equivalent,name=defaults.clusters.camunda.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// +kubebuilder:webhook:verbs=create;update;delete,path=/validate-camunda-m-crossplane-io-v1beta1-cluster,mutating=false,failurePolicy=fail,groups=camunda.m.crossplane.io,resources=clusters,versions=v1beta1,name=clusters.camunda.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// +kubebuilder:webhook:verbs=create,path=/mutate-camunda-m-crossplane-io-v1beta1-cluster,mutating=true,failurePolicy=fail,groups=camunda.m.crossplane.io,resources=clusters,versions=v1beta1,name=defaults.clusters.camunda.m.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// A clusterResource is a Cluster managed resource of either scope.
+type clusterResource interface {
+	resource.Managed
+	GetParameters() *v1beta1.ClusterParameters
+	DeletionProtected() bool
+}
+
+// Setup adds defaulting and validating webhooks for cluster scoped and
+// namespaced Cluster managed resources. The parameters of a Cluster are
+// checked against the Console API's catalog if the EnableCatalogValidation
+// feature is enabled.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	v := &validator{}
+	if o.Features.Enabled(features.EnableCatalogValidation) {
+		v.catalog = providerConfigCatalog(mgr.GetClient())
+	}
+	for _, obj := range []runtime.Object{&v1beta1.Cluster{}, &namespacedv1beta1.Cluster{}} {
+		if err := ctrl.NewWebhookManagedBy(mgr).
+			For(obj).
+			WithDefaulter(&defaulter{kube: mgr.GetClient()}).
+			WithValidator(v).
+			Complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A defaulter fills the empty parameters of new Clusters from the cluster
+// defaults of their ProviderConfig.
+type defaulter struct {
+	kube client.Reader
+}
+
+// Default applies the cluster defaults of the Cluster's ProviderConfig. It
+// leaves Clusters that import an existing cluster alone, since their empty
+// parameters are late-initialized from the cluster instead.
+func (d *defaulter) Default(ctx context.Context, obj runtime.Object) error {
+	cr, ok := obj.(clusterResource)
+	if !ok {
+		return errors.New(errNotCluster)
+	}
+	if meta.GetExternalName(cr) != "" || cr.GetProviderConfigReference() == nil {
+		return nil
+	}
+	pc, err := config.Resolve(ctx, d.kube, cr)
+	if err != nil {
+		// The ProviderConfig may be created after the Cluster, in which case
+		// its defaults are applied when the cluster is created.
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetPC)
+	}
+	cr.GetParameters().ApplyDefaults(pc.Spec.ClusterDefaults)
+	return nil
+}
+
+// A catalogFn returns the cluster parameters a Cluster may use.
+type catalogFn func(ctx context.Context, cr resource.Managed) (*camunda.Parameters, error)
+
+// providerConfigCatalog returns the cluster parameters offered by the Console
+// API to the ProviderConfig of a Cluster.
+func providerConfigCatalog(kube client.Client) catalogFn {
+	return func(ctx context.Context, cr resource.Managed) (*camunda.Parameters, error) {
+		pc, err := config.Resolve(ctx, kube, cr)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetPC)
+		}
+		data, err := pc.Credentials(ctx, kube)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetCreds)
+		}
+		svc, err := camunda.NewService(ctx, pc.Key, data)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewService)
+		}
+		p, err := svc.GetParameters(ctx)
+		return p, errors.Wrap(err, errGetParameters)
+	}
+}
+
+// A validator validates Cluster managed resources.
+type validator struct {
+	catalog catalogFn
+}
+
+// ValidateCreate rejects Clusters with malformed or, if a catalog is
+// configured, unknown parameters.
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	cr, ok := obj.(clusterResource)
+	if !ok {
+		return errors.New(errNotCluster)
+	}
+	errs, err := v.validate(ctx, cr, nil)
+	if err != nil {
+		return err
+	}
+	return invalid(cr, errs)
+}
+
+// ValidateUpdate validates the parameters an update changes, so that a Cluster
+// whose parameters the catalog no longer offers can still be updated otherwise.
+// It additionally rejects changes to the region and plan type of a Cluster
+// once they are set, since a cluster cannot be moved or re-planned. Clusters
+// that are being deleted are not validated, so that their finalizer can always
+// be removed.
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	cr, ok := newObj.(clusterResource)
+	if !ok {
+		return errors.New(errNotCluster)
+	}
+	old, ok := oldObj.(clusterResource)
+	if !ok {
+		return errors.New(errNotCluster)
+	}
+	if meta.WasDeleted(cr) {
+		return nil
+	}
+	errs, err := v.validate(ctx, cr, old)
+	if err != nil {
+		return err
+	}
+	fp := field.NewPath("spec", "forProvider")
+	for _, f := range []struct {
+		name     string
+		old, new string
+	}{
+		{name: "region", old: old.GetParameters().Region, new: cr.GetParameters().Region},
+		{name: "planType", old: old.GetParameters().PlanType, new: cr.GetParameters().PlanType},
+	} {
+		if f.old != "" && f.new != f.old {
+			errs = append(errs, field.Invalid(fp.Child(f.name), f.new, errImmutable))
+		}
+	}
+	return invalid(cr, errs)
+}
+
+// ValidateDelete rejects the deletion of a Cluster that is protected from
+// deletion, unless its external resource would be orphaned anyway.
+func (v *validator) ValidateDelete(_ context.Context, obj runtime.Object) error {
+	cr, ok := obj.(clusterResource)
+	if !ok {
+		return errors.New(errNotCluster)
+	}
+	if cr.GetDeletionPolicy() == xpv1.DeletionOrphan {
+		return nil
+	}
+	if cr.DeletionProtected() {
+		return errors.New(errDeletionProtected)
+	}
+	return nil
+}
+
+// validate returns the problems with the parameters of the supplied Cluster
+// that differ from those of the supplied old Cluster, or with all parameters
+// if there is no old Cluster. The generation is checked again whenever the
+// channel changes. The catalog is only fetched if there is a parameter to
+// check; validate returns an error if it cannot be fetched.
+func (v *validator) validate(ctx context.Context, cr, old clusterResource) (field.ErrorList, error) {
+	p := *cr.GetParameters()
+	var o v1beta1.ClusterParameters
+	if old != nil {
+		o = *old.GetParameters()
+	}
+	fp := field.NewPath("spec", "forProvider")
+	errs := validateNamespaced(cr)
+	check := map[string]bool{}
+	for _, f := range []struct {
+		name    string
+		value   string
+		changed bool
+	}{
+		{name: "channel", value: p.Channel, changed: old == nil || p.Channel != o.Channel},
+		{name: "generation", value: p.Generation, changed: old == nil || p.Generation != o.Generation || p.Channel != o.Channel},
+		{name: "region", value: p.Region, changed: old == nil || p.Region != o.Region},
+		{name: "planType", value: p.PlanType, changed: old == nil || p.PlanType != o.PlanType},
+	} {
+		if f.value == "" || !f.changed {
+			continue
+		}
+		check[fp.Child(f.name).String()] = true
+		if _, err := uuid.Parse(f.value); err != nil {
+			errs = append(errs, field.Invalid(fp.Child(f.name), f.value, errNotUUID))
+		}
+	}
+	if len(errs) > 0 || len(check) == 0 || v.catalog == nil {
+		return errs, nil
+	}
+
+	c, err := v.catalog(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range validateCatalog(fp, p, c) {
+		if check[e.Field] {
+			errs = append(errs, e)
+		}
+	}
+	return errs, nil
+}
+
+// validateNamespaced returns the problems with the fields of the supplied
+// Cluster that a namespaced Cluster does not support.
+func validateNamespaced(cr clusterResource) field.ErrorList {
+	if n, ok := cr.(*namespacedv1beta1.Cluster); ok && n.Spec.PublishConnectionDetailsTo != nil {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "publishConnectionDetailsTo"), errPublishConnectionDetails)}
+	}
+	return nil
+}
+
+// validateCatalog returns the parameters that are not offered by the supplied
+// catalog. Parameters that are not set are not checked.
+func validateCatalog(fp *field.Path, p v1beta1.ClusterParameters, c *camunda.Parameters) field.ErrorList {
+	errs := field.ErrorList{}
+
+	var channel *camunda.Channel
+	for i := range c.Channels {
+		if c.Channels[i].ID == p.Channel {
+			channel = &c.Channels[i]
+		}
+	}
+	if p.Channel != "" && channel == nil {
+		errs = append(errs, field.Invalid(fp.Child("channel"), p.Channel, errUnknownChannel))
+	}
+	if p.Generation != "" && channel != nil && !contains(channel.Generations, p.Generation) {
+		errs = append(errs, field.Invalid(fp.Child("generation"), p.Generation, errUnknownGeneration))
+	}
+	if p.Region != "" && !contains(c.Regions, p.Region) {
+		errs = append(errs, field.Invalid(fp.Child("region"), p.Region, errUnknownRegion))
+	}
+	if p.PlanType != "" && !contains(c.PlanTypes, p.PlanType) {
+		errs = append(errs, field.Invalid(fp.Child("planType"), p.PlanType, errUnknownPlanType))
+	}
+	return errs
+}
+
+func contains(l []camunda.Parameter, id string) bool {
+	for _, e := range l {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// invalid returns an Invalid API error for the supplied Cluster if there are
+// any problems.
+func invalid(cr clusterResource, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	gk := v1beta1.ClusterGroupVersionKind.GroupKind()
+	if _, ok := cr.(*namespacedv1beta1.Cluster); ok {
+		gk = namespacedv1beta1.ClusterGroupVersionKind.GroupKind()
+	}
+	return kerrors.NewInvalid(gk, cr.GetName(), errs)
+}
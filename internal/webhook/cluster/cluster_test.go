@@ -0,0 +1,468 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-camunda/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/crossplane/provider-camunda/apis/namespaced/v1beta1"
+	apisv1beta1 "github.com/crossplane/provider-camunda/apis/v1beta1"
+	"github.com/crossplane/provider-camunda/internal/camunda"
+)
+
+func TestValidateDelete(t *testing.T) {
+	type args struct {
+		obj runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NotCluster": {
+			reason: "An error should be returned if the object is not a Cluster.",
+			args:   args{obj: &v1beta1.ClusterList{}},
+			want:   errors.New(errNotCluster),
+		},
+		"Unprotected": {
+			reason: "A Cluster without deletion protection may be deleted.",
+			args:   args{obj: &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"}}},
+		},
+		"ProtectedByField": {
+			reason: "A Cluster with deletionProtection set must not be deleted.",
+			args: args{obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{DeletionProtection: true}},
+			}},
+			want: errors.New(errDeletionProtected),
+		},
+		"ProtectedByAnnotation": {
+			reason: "A Cluster with the deletion protection annotation must not be deleted.",
+			args: args{obj: &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{
+				Name:        "cool-cluster",
+				Annotations: map[string]string{v1beta1.AnnotationKeyDeletionProtection: "true"},
+			}}},
+			want: errors.New(errDeletionProtected),
+		},
+		"AnnotationNotTrue": {
+			reason: "A deletion protection annotation that is not \"true\" does not protect a Cluster.",
+			args: args{obj: &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{
+				Name:        "cool-cluster",
+				Annotations: map[string]string{v1beta1.AnnotationKeyDeletionProtection: "false"},
+			}}},
+		},
+		"ProtectedButOrphaned": {
+			reason: "A protected Cluster may be deleted if its external resource is orphaned.",
+			args: args{obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{DeletionPolicy: xpv1.DeletionOrphan},
+					ForProvider:  v1beta1.ClusterParameters{DeletionProtection: true},
+				},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{}
+			err := v.ValidateDelete(context.Background(), tc.args.obj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateDelete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+const (
+	channelID    = "6bdcf0e5-ff1e-4f7c-a5a3-0b6a1e7f2b10"
+	generationID = "0b3c7a43-1c8e-4d5b-9fcd-3f3c3d0e8a21"
+	regionID     = "2f6c1d8e-9b7a-4c3e-8d2f-5a4b3c2d1e01"
+	planTypeID   = "7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	otherID      = "ffffffff-ffff-4fff-bfff-ffffffffffff"
+)
+
+func validParameters() v1beta1.ClusterParameters {
+	return v1beta1.ClusterParameters{Channel: channelID, Generation: generationID, Region: regionID, PlanType: planTypeID}
+}
+
+func catalog() *camunda.Parameters {
+	e := func(id string) camunda.Parameter {
+		return camunda.Parameter{ID: id}
+	}
+	return &camunda.Parameters{
+		Channels: []camunda.Channel{{
+			Parameter:   e(channelID),
+			Generations: []camunda.Parameter{e(generationID)},
+		}},
+		Regions:   []camunda.Parameter{e(regionID)},
+		PlanTypes: []camunda.Parameter{e(planTypeID)},
+	}
+}
+
+func invalidCluster(errs ...*field.Error) error {
+	return kerrors.NewInvalid(v1beta1.ClusterGroupVersionKind.GroupKind(), "cool-cluster", errs)
+}
+
+func TestValidateCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+	fp := field.NewPath("spec", "forProvider")
+
+	type fields struct {
+		catalog catalogFn
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		obj    runtime.Object
+		want   error
+	}{
+		"NotCluster": {
+			reason: "An error should be returned if the object is not a Cluster.",
+			obj:    &v1beta1.ClusterList{},
+			want:   errors.New(errNotCluster),
+		},
+		"Empty": {
+			reason: "A Cluster without parameters may be created, e.g. to import a cluster.",
+			obj:    &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"}},
+		},
+		"NotUUID": {
+			reason: "Parameters that are not UUIDs should be rejected.",
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Region: "europe-west1", Channel: channelID}},
+			},
+			want: invalidCluster(field.Invalid(fp.Child("region"), "europe-west1", errNotUUID)),
+		},
+		"CatalogError": {
+			reason: "An error should be returned if the catalog cannot be fetched.",
+			fields: fields{catalog: func(context.Context, resource.Managed) (*camunda.Parameters, error) { return nil, errBoom }},
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: validParameters()},
+			},
+			want: errBoom,
+		},
+		"InCatalog": {
+			reason: "Parameters that are offered by the catalog should be accepted.",
+			fields: fields{catalog: func(context.Context, resource.Managed) (*camunda.Parameters, error) { return catalog(), nil }},
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: validParameters()},
+			},
+		},
+		"NotInCatalog": {
+			reason: "Parameters that are not offered by the catalog should be rejected.",
+			fields: fields{catalog: func(context.Context, resource.Managed) (*camunda.Parameters, error) { return catalog(), nil }},
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Channel: channelID, Generation: otherID, Region: otherID, PlanType: planTypeID}},
+			},
+			want: invalidCluster(
+				field.Invalid(fp.Child("generation"), otherID, errUnknownGeneration),
+				field.Invalid(fp.Child("region"), otherID, errUnknownRegion),
+			),
+		},
+		"NamespacedPublishConnectionDetails": {
+			reason: "A namespaced Cluster should not publish connection details to a cluster scoped StoreConfig.",
+			obj: &namespacedv1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cool-cluster"},
+				Spec: namespacedv1beta1.ClusterSpec{ResourceSpec: namespacedv1beta1.ResourceSpec{
+					PublishConnectionDetailsTo: &xpv1.PublishConnectionDetailsTo{Name: "cool-cluster"},
+				}},
+			},
+			want: kerrors.NewInvalid(namespacedv1beta1.ClusterGroupVersionKind.GroupKind(), "cool-cluster", field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "publishConnectionDetailsTo"), errPublishConnectionDetails),
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{catalog: tc.fields.catalog}
+			err := v.ValidateCreate(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateCreate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+	fp := field.NewPath("spec", "forProvider")
+	now := metav1.Now()
+	retired := v1beta1.ClusterParameters{Channel: channelID, Generation: generationID, Region: otherID, PlanType: planTypeID}
+
+	type fields struct {
+		catalog catalogFn
+	}
+
+	type args struct {
+		oldObj runtime.Object
+		newObj runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		args   args
+		want   error
+	}{
+		"UnchangedSkipsCatalog": {
+			reason: "An update that does not change the parameters should not fetch the catalog.",
+			fields: fields{catalog: func(context.Context, resource.Managed) (*camunda.Parameters, error) { return nil, errBoom }},
+			args: args{
+				oldObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: retired},
+				},
+				newObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Channel: channelID, Generation: generationID, Region: otherID, PlanType: planTypeID, DeletionProtection: true}},
+				},
+			},
+		},
+		"ChangedNotInCatalog": {
+			reason: "Only the parameters an update changes should be checked against the catalog.",
+			fields: fields{catalog: func(context.Context, resource.Managed) (*camunda.Parameters, error) { return catalog(), nil }},
+			args: args{
+				oldObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: retired},
+				},
+				newObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Channel: channelID, Generation: otherID, Region: otherID, PlanType: planTypeID}},
+				},
+			},
+			want: invalidCluster(field.Invalid(fp.Child("generation"), otherID, errUnknownGeneration)),
+		},
+		"Deleting": {
+			reason: "A Cluster that is being deleted should not be validated, so that its finalizer can be removed.",
+			fields: fields{catalog: func(context.Context, resource.Managed) (*camunda.Parameters, error) { return nil, errBoom }},
+			args: args{
+				oldObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: validParameters()},
+				},
+				newObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", DeletionTimestamp: &now},
+					Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Region: otherID}},
+				},
+			},
+		},
+		"LateInitialized": {
+			reason: "Parameters that were not set may be set, e.g. by late-initialization.",
+			args: args{
+				oldObj: &v1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"}},
+				newObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: validParameters()},
+				},
+			},
+		},
+		"Unchanged": {
+			reason: "An update that does not change immutable parameters should be accepted.",
+			args: args{
+				oldObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: validParameters()},
+				},
+				newObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Channel: channelID, Generation: generationID, Region: regionID, PlanType: planTypeID, DeletionProtection: true}},
+				},
+			},
+		},
+		"RegionChanged": {
+			reason: "Changing the region of a Cluster should be rejected.",
+			args: args{
+				oldObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: validParameters()},
+				},
+				newObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Channel: channelID, Generation: generationID, Region: otherID, PlanType: planTypeID}},
+				},
+			},
+			want: invalidCluster(field.Invalid(fp.Child("region"), otherID, errImmutable)),
+		},
+		"PlanTypeRemoved": {
+			reason: "Removing the plan type of a Cluster should be rejected.",
+			args: args{
+				oldObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: validParameters()},
+				},
+				newObj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec:       v1beta1.ClusterSpec{ForProvider: v1beta1.ClusterParameters{Channel: channelID, Generation: generationID, Region: regionID}},
+				},
+			},
+			want: invalidCluster(field.Invalid(fp.Child("planType"), "", errImmutable)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{catalog: tc.fields.catalog}
+			err := v.ValidateUpdate(context.Background(), tc.args.oldObj, tc.args.newObj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateUpdate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	getPC := test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*apisv1beta1.ProviderConfig).Spec.ClusterDefaults = &apisv1beta1.ClusterDefaults{
+			Channel:    channelID,
+			Generation: generationID,
+			Region:     regionID,
+			PlanType:   planTypeID,
+		}
+		return nil
+	})
+
+	type want struct {
+		obj runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Reader
+		obj    runtime.Object
+		want   want
+	}{
+		"NotCluster": {
+			reason: "An error should be returned if the object is not a Cluster.",
+			obj:    &v1beta1.ClusterList{},
+			want:   want{obj: &v1beta1.ClusterList{}, err: errors.New(errNotCluster)},
+		},
+		"Imported": {
+			reason: "The defaults should not be applied to a Cluster that imports a cluster.",
+			kube:   &test.MockClient{MockGet: getPC},
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: "cool-id"}},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+				},
+			},
+			want: want{obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster", Annotations: map[string]string{meta.AnnotationKeyExternalName: "cool-id"}},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+				},
+			}},
+		},
+		"ProviderConfigNotFound": {
+			reason: "The Cluster should be admitted unchanged if its ProviderConfig does not exist yet.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "default"))},
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+				},
+			},
+			want: want{obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+				},
+			}},
+		},
+		"GetProviderConfigError": {
+			reason: "An error should be returned if the ProviderConfig cannot be read.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+				},
+			},
+			want: want{
+				obj: &v1beta1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+					Spec: v1beta1.ClusterSpec{
+						ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+					},
+				},
+				err: errors.Wrap(errBoom, errGetPC),
+			},
+		},
+		"Defaulted": {
+			reason: "Empty parameters should be filled from the ProviderConfig's cluster defaults.",
+			kube:   &test.MockClient{MockGet: getPC},
+			obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+					ForProvider:  v1beta1.ClusterParameters{Region: otherID},
+				},
+			},
+			want: want{obj: &v1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cool-cluster"},
+				Spec: v1beta1.ClusterSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+					ForProvider: v1beta1.ClusterParameters{
+						Channel:    channelID,
+						Generation: generationID,
+						Region:     otherID,
+						PlanType:   planTypeID,
+					},
+				},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &defaulter{kube: tc.kube}
+			err := d.Default(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nd.Default(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obj, tc.obj); diff != "" {
+				t.Errorf("\n%s\nd.Default(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config contains the webhook of ProviderConfigs.
+package config
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+
+	"github.com/crossplane/provider-camunda/apis/v1beta1"
+)
+
+// Setup adds a webhook that converts ProviderConfigs between their versions.
+func Setup(mgr ctrl.Manager, _ controller.Options) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1beta1.ProviderConfig{}).
+		Complete()
+}
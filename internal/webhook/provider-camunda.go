@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook contains the admission and conversion webhooks of the Camunda
+// provider.
+package webhook
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-camunda/internal/webhook/client"
+	"github.com/crossplane/provider-camunda/internal/webhook/cluster"
+	"github.com/crossplane/provider-camunda/internal/webhook/config"
+)
+
+// Setup creates Camunda webhooks with the supplied options and adds them to
+// the supplied manager. Besides admission webhooks these include the webhook
+// that converts resources between their API versions.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	for _, setup := range []func(ctrl.Manager, controller.Options) error{
+		cluster.Setup,
+		client.Setup,
+		config.Setup,
+	} {
+		if err := setup(mgr, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}